@@ -0,0 +1,48 @@
+// acrun.libsonnet is bundled with acrun. Import it with `local acrun = import 'acrun.libsonnet';`.
+{
+  network:: {
+    // public returns a network configuration for the PUBLIC network mode.
+    public():: {
+      networkMode: 'PUBLIC',
+    },
+    // vpc returns a network configuration for the VPC network mode.
+    vpc(subnets, securityGroups):: {
+      networkMode: 'VPC',
+      networkModeConfig: {
+        subnets: subnets,
+        securityGroups: securityGroups,
+      },
+    },
+  },
+
+  protocol:: {
+    mcp:: { serverProtocol: 'MCP' },
+    http:: { serverProtocol: 'HTTP' },
+    a2a:: { serverProtocol: 'A2A' },
+  },
+
+  authorizer:: {
+    // jwt returns a custom JWT authorizer configuration. Empty lists are omitted.
+    jwt(discoveryUrl, clients=[], audiences=[]):: {
+      customJWTAuthorizer: {
+        discoveryUrl: discoveryUrl,
+        [if std.length(clients) > 0 then 'allowedClients']: clients,
+        [if std.length(audiences) > 0 then 'allowedAudience']: audiences,
+      },
+    },
+    // cognitoJwt returns a custom JWT authorizer configuration for an Amazon Cognito user pool.
+    cognitoJwt(region, userPoolId, clients=[], audiences=[])::
+      self.jwt(
+        'https://cognito-idp.%s.amazonaws.com/%s/.well-known/openid-configuration' % [region, userPoolId],
+        clients,
+        audiences,
+      ),
+  },
+
+  requestHeaders:: {
+    // allowList returns a request header configuration that passes the headers to the agent.
+    allowList(headers):: {
+      allowList: headers,
+    },
+  },
+}
@@ -2,6 +2,7 @@
 package acrun
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -9,17 +10,19 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"sync"
 
-	"github.com/Songmu/prompter"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcore"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/google/go-jsonnet"
 )
@@ -48,28 +51,70 @@ type App struct {
 	agentRuntimeFilepath string
 	ctrlClient           BedrockAgentCoreControlClient
 	client               BedrockAgentCoreClient
+	s3Client             S3Client
+	smClient             SecretsManagerClient
 	vm                   *jsonnet.VM
 
 	cacheMu         sync.RWMutex
 	cacheIDbyNames  map[string]string
 	cacheARNbyNames map[string]string
 
-	verbose bool
-	stdout  io.Writer
-	stderr  io.Writer
+	verbose   bool
+	stdin     io.Reader
+	stdout    io.Writer
+	stderr    io.Writer
+	logger    *slog.Logger
+	confirmer Confirmer
+	// masker is nil with --show-secrets.
+	masker *secretMasker
+
+	serverDefaultsFilepath string
 }
 
 type GlobalOption struct {
-	AgentRuntime string            `help:"Agent runtime file path"  json:"agent_runtime,omitempty"`
-	TFState      string            `name:"tfstate" help:"Terraform state file URL (s3://... or local path)" env:"ACRUN_TFSTATE" json:"tfstate,omitempty"`
-	ExtStr       map[string]string `help:"Set external string variable for Jsonnet VM" env:"ACRUN_EXTSTR" json:"ext_strs,omitempty"`
-	ExtCode      map[string]string `help:"Set external code variable for Jsonnet VM" env:"ACRUN_EXTCODE" json:"ext_codes,omitempty"`
-	Verbose      bool              `name:"verbose" short:"v" help:"enable verbose logging" default:"false" json:"verbose,omitempty"`
-	Region       string            `name:"region" help:"AWS Region" env:"AWS_REGION,ACRUN_REGION" json:"region,omitempty"`
-	Profile      string            `name:"profile" help:"AWS CLI profile name" env:"AWS_PROFILE,ACRUN_PROFILE" json:"profile,omitempty"`
+	AgentRuntime   string            `help:"Agent runtime file path"  json:"agent_runtime,omitempty"`
+	TFState        string            `name:"tfstate" help:"Terraform state file URL (s3://... or local path)" env:"ACRUN_TFSTATE" json:"tfstate,omitempty"`
+	ExtStr         map[string]string `help:"Set external string variable for Jsonnet VM" env:"ACRUN_EXTSTR" json:"ext_strs,omitempty"`
+	ExtCode        map[string]string `help:"Set external code variable for Jsonnet VM" env:"ACRUN_EXTCODE" json:"ext_codes,omitempty"`
+	TLAStr         map[string]string `name:"tla-str" help:"Set top-level string argument for Jsonnet VM" env:"ACRUN_TLASTR" json:"tla_strs,omitempty"`
+	TLACode        map[string]string `name:"tla-code" help:"Set top-level code argument for Jsonnet VM" env:"ACRUN_TLACODE" json:"tla_codes,omitempty"`
+	TLAStrFile     map[string]string `name:"tla-str-file" help:"Set top-level string argument for Jsonnet VM from the file" json:"tla_str_files,omitempty"`
+	TLACodeFile    map[string]string `name:"tla-code-file" help:"Set top-level code argument for Jsonnet VM from the file" json:"tla_code_files,omitempty"`
+	JPath          []string          `name:"jpath" short:"J" help:"Add a library search path for Jsonnet imports" env:"ACRUN_JPATH" json:"jpath,omitempty"`
+	Verbose        bool              `name:"verbose" short:"v" help:"enable verbose logging" default:"false" json:"verbose,omitempty"`
+	Region         string            `name:"region" help:"AWS Region" env:"AWS_REGION,ACRUN_REGION" json:"region,omitempty"`
+	Profile        string            `name:"profile" help:"AWS CLI profile name" env:"AWS_PROFILE,ACRUN_PROFILE" json:"profile,omitempty"`
+	ServerDefaults string            `name:"server-defaults" help:"file of server default values to suppress in diff, in addition to the built-in ones" env:"ACRUN_SERVER_DEFAULTS" json:"server_defaults,omitempty"`
+	ShowSecrets    bool              `name:"show-secrets" help:"show secret environment variable values in diff, render and verbose output" default:"false" json:"show_secrets,omitempty"`
+}
+
+// Option configures the App.
+type Option func(*App)
+
+// WithLogger sets the logger. The default is slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(app *App) {
+		app.logger = logger
+	}
+}
+
+// WithConfirmer sets the Confirmer that confirms destructive operations.
+// Without it, operations that require a confirmation fail with ErrConfirmationRequired unless forced.
+func WithConfirmer(c Confirmer) Option {
+	return func(app *App) {
+		app.confirmer = c
+	}
 }
 
-func New(ctx context.Context, opts *GlobalOption) (*App, error) {
+// WithSecretsManagerClient sets the client used by the secretsManager native functions of Jsonnet.
+// Without it, these functions fail to evaluate.
+func WithSecretsManagerClient(c SecretsManagerClient) Option {
+	return func(app *App) {
+		app.smClient = c
+	}
+}
+
+func New(ctx context.Context, opts *GlobalOption, appOpts ...Option) (*App, error) {
 	awsOpts := []func(*config.LoadOptions) error{}
 	if opts.Region != "" {
 		awsOpts = append(awsOpts, config.WithRegion(opts.Region))
@@ -89,6 +134,8 @@ func New(ctx context.Context, opts *GlobalOption) (*App, error) {
 		bedrockagentcore.NewFromConfig(awsCfg),
 		ecr.NewFromConfig(awsCfg),
 		sts.NewFromConfig(awsCfg),
+		s3.NewFromConfig(awsCfg),
+		append([]Option{WithSecretsManagerClient(secretsmanager.NewFromConfig(awsCfg))}, appOpts...)...,
 	)
 }
 
@@ -100,7 +147,24 @@ func NewWithClient(
 	client BedrockAgentCoreClient,
 	ecrClient ECRClient,
 	stsClient STSClient,
+	s3Client S3Client,
+	appOpts ...Option,
 ) (*App, error) {
+	app := &App{
+		ctrlClient:      ctrlClient,
+		client:          client,
+		s3Client:        s3Client,
+		cacheIDbyNames:  make(map[string]string),
+		cacheARNbyNames: make(map[string]string),
+		stdin:           os.Stdin,
+		stdout:          os.Stdout,
+		stderr:          os.Stderr,
+		logger:          slog.Default(),
+		verbose:         opts.Verbose,
+	}
+	for _, fn := range appOpts {
+		fn(app)
+	}
 	if opts.AgentRuntime == "" {
 		cwd, err := os.Getwd()
 		if err != nil {
@@ -109,24 +173,20 @@ func NewWithClient(
 		for _, fn := range DefaultAgentRuntimeFilenames {
 			path := filepath.Join(cwd, fn)
 			if _, err := os.Stat(path); err == nil {
-				slog.DebugContext(ctx, "Found agent runtime file", "file", path)
+				app.logger.DebugContext(ctx, "Found agent runtime file", "file", path)
 				opts.AgentRuntime = path
 				break
 			}
 		}
 	}
 
-	return &App{
-		agentRuntimeFilepath: opts.AgentRuntime,
-		ctrlClient:           ctrlClient,
-		client:               client,
-		cacheIDbyNames:       make(map[string]string),
-		cacheARNbyNames:      make(map[string]string),
-		vm:                   MakeVM(ctx, stsClient, ecrClient, awsCfg, opts),
-		stdout:               os.Stdout,
-		stderr:               os.Stderr,
-		verbose:              opts.Verbose,
-	}, nil
+	if !opts.ShowSecrets {
+		app.masker = newSecretMasker()
+	}
+	app.agentRuntimeFilepath = opts.AgentRuntime
+	app.serverDefaultsFilepath = opts.ServerDefaults
+	app.vm = makeVM(ctx, app.logger, stsClient, ecrClient, app.smClient, app.masker, awsCfg, opts)
+	return app, nil
 }
 
 var (
@@ -138,13 +198,28 @@ func (app *App) SetOutput(stdout, stderr io.Writer) {
 	app.stderr = stderr
 }
 
+// SetInput sets the reader for the payload of invoke. The default is os.Stdin.
+func (app *App) SetInput(stdin io.Reader) {
+	app.stdin = stdin
+}
+
 func (app *App) DumpIfVerbose(ctx context.Context, title string, v interface{}) {
 	if !app.verbose {
 		return
 	}
-	bs, err := json.MarshalIndent(v, "", "  ")
+	bs, err := json.Marshal(v)
 	if err != nil {
-		slog.WarnContext(ctx, "failed to marshal for dump", "title", title, "error", err)
+		app.logger.WarnContext(ctx, "failed to marshal for dump", "title", title, "error", err)
+		return
+	}
+	var doc any
+	if err := json.Unmarshal(bs, &doc); err != nil {
+		app.logger.WarnContext(ctx, "failed to unmarshal for dump", "title", title, "error", err)
+		return
+	}
+	bs, err = json.MarshalIndent(app.masker.maskDocument(doc), "", "  ")
+	if err != nil {
+		app.logger.WarnContext(ctx, "failed to marshal for dump", "title", title, "error", err)
 		return
 	}
 	fmt.Fprintf(app.stderr, "%s:\n%s\n", title, string(bs))
@@ -177,7 +252,7 @@ func (app *App) GetAgentRuntimeVersionByEndpointName(ctx context.Context, name s
 }
 
 func (app *App) GetAgentRuntime(ctx context.Context, name *string, qualifier *string) (*bedrockagentcorecontrol.GetAgentRuntimeOutput, error) {
-	slog.DebugContext(ctx, "resolving agent runtime", "name", aws.ToString(name), "qualifier", aws.ToString(qualifier))
+	app.logger.DebugContext(ctx, "resolving agent runtime", "name", aws.ToString(name), "qualifier", aws.ToString(qualifier))
 	id, err := app.GetAgentRuntimeIDByName(ctx, *name)
 	if err != nil {
 		return nil, fmt.Errorf("get agent runtime ID by name: %w", err)
@@ -193,7 +268,7 @@ func (app *App) GetAgentRuntime(ctx context.Context, name *string, qualifier *st
 	} else {
 		version = q
 	}
-	slog.DebugContext(ctx, "resolved qualifier to version", "qualifier", q, "version", version)
+	app.logger.DebugContext(ctx, "resolved qualifier to version", "qualifier", q, "version", version)
 	resp, err := app.ctrlClient.GetAgentRuntime(ctx, &bedrockagentcorecontrol.GetAgentRuntimeInput{
 		AgentRuntimeId:      aws.String(id),
 		AgentRuntimeVersion: aws.String(version),
@@ -233,7 +308,7 @@ func (app *App) GetAgentRuntimeARNByName(ctx context.Context, name string) (stri
 func (app *App) findAgentRuntimeByName(ctx context.Context, name string) (string, string, error) {
 	app.cacheMu.Lock()
 	defer app.cacheMu.Unlock()
-	slog.DebugContext(ctx, "Fetching AgentRuntimes for resolving name to ID and ARN", "name", name)
+	app.logger.DebugContext(ctx, "Fetching AgentRuntimes for resolving name to ID and ARN", "name", name)
 
 	p := bedrockagentcorecontrol.NewListAgentRuntimesPaginator(
 		app.ctrlClient,
@@ -245,7 +320,7 @@ func (app *App) findAgentRuntimeByName(ctx context.Context, name string) (string
 			return "", "", fmt.Errorf("ListAgentRuntimes: %w", err)
 		}
 		for _, rt := range out.AgentRuntimes {
-			slog.DebugContext(ctx, "Found AgentRuntime", "id", *rt.AgentRuntimeId, "name", *rt.AgentRuntimeName)
+			app.logger.DebugContext(ctx, "Found AgentRuntime", "id", *rt.AgentRuntimeId, "name", *rt.AgentRuntimeName)
 			if *rt.AgentRuntimeName == name {
 				app.cacheIDbyNames[name] = *rt.AgentRuntimeId
 				app.cacheARNbyNames[name] = *rt.AgentRuntimeArn
@@ -253,52 +328,148 @@ func (app *App) findAgentRuntimeByName(ctx context.Context, name string) (string
 			}
 		}
 	}
-	slog.DebugContext(ctx, "No AgentRuntime found with the specified name", "name", name)
+	app.logger.DebugContext(ctx, "No AgentRuntime found with the specified name", "name", name)
 	return "", "", ErrAgentRuntimeNotFound
 }
 
-func (app *App) saveFile(ctx context.Context, path string, b []byte, mode os.FileMode, force bool) error {
-	slog.DebugContext(ctx, "writing file", "file", path, "mode", mode)
+// saveFile writes the file, confirming to overwrite an existing one unless forced.
+// It reports whether the file was written; false when the overwrite was declined.
+func (app *App) saveFile(ctx context.Context, path string, b []byte, mode os.FileMode, force bool) (bool, error) {
+	app.logger.DebugContext(ctx, "writing file", "file", path, "mode", mode)
 	if _, err := os.Stat(path); err == nil {
-		ok := force || prompter.YN(fmt.Sprintf("Overwrite existing file %s?", path), false)
+		ok := force
+		if !ok {
+			var err error
+			ok, err = app.confirm(ctx, fmt.Sprintf("Overwrite existing file %s?", path))
+			if err != nil {
+				return false, fmt.Errorf("overwrite %s: %w", path, err)
+			}
+		}
 		if !ok {
 			if ctx.Err() != nil {
-				return ctx.Err()
+				return false, ctx.Err()
 			}
-			return nil
+			app.logger.InfoContext(ctx, "overwrite declined, file is not written", "file", path)
+			return false, nil
 		}
 	}
-	return os.WriteFile(path, b, mode)
+	if err := os.WriteFile(path, b, mode); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-func (app *App) loadAgentRuntimeFile(ctx context.Context) (*AgentRuntime, error) {
-	path := app.agentRuntimeFilepath
-	slog.InfoContext(ctx, "loading agent runtime file", "file", path)
+// readJSONOrJsonnetFile reads the file and evaluates it with the Jsonnet VM when the extension is .jsonnet.
+func (app *App) readJSONOrJsonnetFile(path string) ([]byte, error) {
 	bs, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read file %s: %w", path, err)
 	}
-	ext := filepath.Ext(path)
-	if ext == ".jsonnet" {
-		jsonStr, err := app.vm.EvaluateAnonymousSnippet(path, string(bs))
-		if err != nil {
-			return nil, fmt.Errorf("failed to evaluate jsonnet: %w", err)
+	if filepath.Ext(path) != ".jsonnet" {
+		return bs, nil
+	}
+	jsonStr, err := app.vm.EvaluateAnonymousSnippet(path, string(bs))
+	if err != nil {
+		if hint := tlaErrorHint(err); hint != "" {
+			return nil, fmt.Errorf("failed to evaluate jsonnet %s: %s: %w", path, hint, err)
 		}
-		bs = []byte(jsonStr)
+		return nil, fmt.Errorf("failed to evaluate jsonnet: %w", err)
 	}
+	return []byte(jsonStr), nil
+}
+
+func (app *App) loadAgentRuntimeFile(ctx context.Context) (*AgentRuntime, error) {
+	def, _, err := app.loadAgentRuntimeFileWithExtension(ctx)
+	return def, err
+}
+
+// AgentRuntimeExtension holds acrun specific fields in the agent runtime file.
+// These fields are stripped before the file is decoded as CreateAgentRuntimeInput.
+type AgentRuntimeExtension struct {
+	CodeSource *CodeSource                `json:"codeSource,omitempty"`
+	Endpoints  map[string]*EndpointConfig `json:"endpoints,omitempty"`
+	// SecretEnvironmentVariables is the glob patterns of environment variable names to mask in addition to DefaultSecretPatterns.
+	SecretEnvironmentVariables []string `json:"secretEnvironmentVariables,omitempty"`
+}
+
+func (app *App) loadAgentRuntimeFileWithExtension(ctx context.Context) (*AgentRuntime, *AgentRuntimeExtension, error) {
+	return app.loadAgentRuntimeFileAt(ctx, app.agentRuntimeFilepath)
+}
+
+// loadAgentRuntimeFileAt loads the agent runtime file at the path instead of the configured one.
+func (app *App) loadAgentRuntimeFileAt(ctx context.Context, path string) (*AgentRuntime, *AgentRuntimeExtension, error) {
+	app.logger.InfoContext(ctx, "loading agent runtime file", "file", path)
+	bs, err := app.readJSONOrJsonnetFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	ext, bs, err := extractAgentRuntimeExtension(bs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("extractAgentRuntimeExtension: %w", err)
+	}
+	app.masker.add(ext.SecretEnvironmentVariables...)
 	def, err := unmarshalAgentRuntime(bs, true)
 	if err != nil {
 		field := extractUnknownFieldKey(err)
 		if field == "" {
-			return nil, fmt.Errorf("unmarshalAgentRuntime: %w", err)
+			return nil, nil, fmt.Errorf("unmarshalAgentRuntime: %w", err)
 		}
-		slog.WarnContext(ctx, "unknown field found in agent runtime file", "file", path, "field", extractUnknownFieldKey(err))
+		app.logger.WarnContext(ctx, "unknown field found in agent runtime file", "file", path, "field", extractUnknownFieldKey(err))
 		def, err = unmarshalAgentRuntime(bs, false)
 		if err != nil {
-			return nil, fmt.Errorf("unmarshalAgentRuntime: %w", err)
+			return nil, nil, fmt.Errorf("unmarshalAgentRuntime: %w", err)
 		}
 	}
-	return def, validateAgentRuntime(def)
+	app.masker.addKeysOfValues(def.EnvironmentVariables)
+	return def, ext, validateAgentRuntime(def)
+}
+
+// extractAgentRuntimeExtension splits the acrun specific fields from the agent runtime JSON.
+func extractAgentRuntimeExtension(bs []byte) (*AgentRuntimeExtension, []byte, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(bs, &raw); err != nil {
+		// not an object; leave it to unmarshalAgentRuntime to report the error
+		return &AgentRuntimeExtension{}, bs, nil
+	}
+	extRaw := map[string]json.RawMessage{}
+	for _, key := range agentRuntimeExtensionKeys {
+		if v, ok := raw[key]; ok {
+			extRaw[key] = v
+			delete(raw, key)
+		}
+	}
+	var ext AgentRuntimeExtension
+	if len(extRaw) == 0 {
+		return &ext, bs, nil
+	}
+	b, err := json.Marshal(extRaw)
+	if err != nil {
+		return nil, nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&ext); err != nil {
+		return nil, nil, err
+	}
+	if err := validateEndpointConfigs(ext.Endpoints); err != nil {
+		return nil, nil, err
+	}
+	for _, p := range ext.SecretEnvironmentVariables {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, nil, fmt.Errorf("invalid secretEnvironmentVariables pattern %q: %w", p, err)
+		}
+	}
+	rest, err := json.Marshal(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &ext, rest, nil
+}
+
+var agentRuntimeExtensionKeys = []string{
+	"codeSource",
+	"endpoints",
+	"secretEnvironmentVariables",
 }
 
 func validateAgentRuntime(def *AgentRuntime) error {
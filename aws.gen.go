@@ -19,8 +19,8 @@ func convertToAgentRuntimeArtifact(v any, strict bool) (types.AgentRuntimeArtifa
 	}
 	var variants struct {
 
-		// CodeConfiguration variant
-		CodeConfiguration *types.CodeConfiguration `json:"codeConfiguration"`
+		// CodeConfiguration variant (decoded after resolving nested unions)
+		CodeConfiguration *json.RawMessage `json:"codeConfiguration"`
 
 		// ContainerConfiguration variant
 		ContainerConfiguration *types.ContainerConfiguration `json:"containerConfiguration"`
@@ -35,8 +35,27 @@ func convertToAgentRuntimeArtifact(v any, strict bool) (types.AgentRuntimeArtifa
 
 	// Check codeConfiguration variant
 	if variants.CodeConfiguration != nil {
+		var value struct {
+			*types.CodeConfiguration
+			Code any `json:"code"`
+		}
+		value.CodeConfiguration = &types.CodeConfiguration{}
+		dec := json.NewDecoder(bytes.NewReader(*variants.CodeConfiguration))
+		if strict {
+			dec.DisallowUnknownFields()
+		}
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		if value.Code != nil {
+			nested, err := convertToCode(value.Code, strict)
+			if err != nil {
+				return nil, err
+			}
+			value.CodeConfiguration.Code = nested
+		}
 		return &types.AgentRuntimeArtifactMemberCodeConfiguration{
-			Value: *variants.CodeConfiguration,
+			Value: *value.CodeConfiguration,
 		}, nil
 	}
 
@@ -57,7 +76,19 @@ func convertFromAgentRuntimeArtifact(v types.AgentRuntimeArtifact) (any, error)
 	}
 	switch v := v.(type) {
 	case *types.AgentRuntimeArtifactMemberCodeConfiguration:
-		bs, err := marshalJSON(v.Value)
+		value := v.Value
+		bs, err := marshalJSON(value, func(opts *marshalJSONOptions) {
+			opts.hooks = append(opts.hooks, func(path, key string, v any) (string, any, error) {
+				if matchJSONKey(path, "$.code") {
+					nested, err := convertFromCode(value.Code)
+					if err != nil {
+						return "", nil, err
+					}
+					return key, nested, nil
+				}
+				return key, v, nil
+			})
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -85,8 +116,8 @@ func convertToAuthorizerConfiguration(v any, strict bool) (types.AuthorizerConfi
 	}
 	var variants struct {
 
-		// CustomJWTAuthorizer variant
-		CustomJWTAuthorizer *types.CustomJWTAuthorizerConfiguration `json:"customJWTAuthorizer"`
+		// CustomJWTAuthorizer variant (decoded after resolving nested unions)
+		CustomJWTAuthorizer *json.RawMessage `json:"customJWTAuthorizer"`
 	}
 	dec := json.NewDecoder(bytes.NewReader(data))
 	if strict {
@@ -98,8 +129,27 @@ func convertToAuthorizerConfiguration(v any, strict bool) (types.AuthorizerConfi
 
 	// Check customJWTAuthorizer variant
 	if variants.CustomJWTAuthorizer != nil {
+		var value struct {
+			*types.CustomJWTAuthorizerConfiguration
+			PrivateEndpoint any `json:"privateEndpoint"`
+		}
+		value.CustomJWTAuthorizerConfiguration = &types.CustomJWTAuthorizerConfiguration{}
+		dec := json.NewDecoder(bytes.NewReader(*variants.CustomJWTAuthorizer))
+		if strict {
+			dec.DisallowUnknownFields()
+		}
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		if value.PrivateEndpoint != nil {
+			nested, err := convertToPrivateEndpoint(value.PrivateEndpoint, strict)
+			if err != nil {
+				return nil, err
+			}
+			value.CustomJWTAuthorizerConfiguration.PrivateEndpoint = nested
+		}
 		return &types.AuthorizerConfigurationMemberCustomJWTAuthorizer{
-			Value: *variants.CustomJWTAuthorizer,
+			Value: *value.CustomJWTAuthorizerConfiguration,
 		}, nil
 	}
 
@@ -113,7 +163,19 @@ func convertFromAuthorizerConfiguration(v types.AuthorizerConfiguration) (any, e
 	}
 	switch v := v.(type) {
 	case *types.AuthorizerConfigurationMemberCustomJWTAuthorizer:
-		bs, err := marshalJSON(v.Value)
+		value := v.Value
+		bs, err := marshalJSON(value, func(opts *marshalJSONOptions) {
+			opts.hooks = append(opts.hooks, func(path, key string, v any) (string, any, error) {
+				if matchJSONKey(path, "$.privateEndpoint") {
+					nested, err := convertFromPrivateEndpoint(value.PrivateEndpoint)
+					if err != nil {
+						return "", nil, err
+					}
+					return key, nested, nil
+				}
+				return key, v, nil
+			})
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -169,6 +231,168 @@ func convertFromRequestHeaderConfiguration(v types.RequestHeaderConfiguration) (
 	}
 }
 
+// convertToCode converts any value to types.Code
+func convertToCode(v any, strict bool) (types.Code, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var variants struct {
+
+		// S3 variant
+		S3 *types.S3Location `json:"s3"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(&variants); err != nil {
+		return nil, err
+	}
+
+	// Check s3 variant
+	if variants.S3 != nil {
+		return &types.CodeMemberS3{
+			Value: *variants.S3,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no valid variant found for Code")
+}
+
+// convertFromCode converts types.Code to map[string]any
+func convertFromCode(v types.Code) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch v := v.(type) {
+	case *types.CodeMemberS3:
+		bs, err := marshalJSON(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"s3": json.RawMessage(bs),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown Code type: %T", v)
+	}
+}
+
+// convertToPrivateEndpoint converts any value to types.PrivateEndpoint
+func convertToPrivateEndpoint(v any, strict bool) (types.PrivateEndpoint, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var variants struct {
+
+		// ManagedVpcResource variant
+		ManagedVpcResource *types.ManagedVpcResource `json:"managedVpcResource"`
+
+		// SelfManagedLatticeResource variant (nested union)
+		SelfManagedLatticeResource any `json:"selfManagedLatticeResource"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(&variants); err != nil {
+		return nil, err
+	}
+
+	// Check managedVpcResource variant
+	if variants.ManagedVpcResource != nil {
+		return &types.PrivateEndpointMemberManagedVpcResource{
+			Value: *variants.ManagedVpcResource,
+		}, nil
+	}
+
+	// Check selfManagedLatticeResource variant
+	if variants.SelfManagedLatticeResource != nil {
+		nested, err := convertToSelfManagedLatticeResource(variants.SelfManagedLatticeResource, strict)
+		if err != nil {
+			return nil, err
+		}
+		return &types.PrivateEndpointMemberSelfManagedLatticeResource{
+			Value: nested,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no valid variant found for PrivateEndpoint")
+}
+
+// convertFromPrivateEndpoint converts types.PrivateEndpoint to map[string]any
+func convertFromPrivateEndpoint(v types.PrivateEndpoint) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch v := v.(type) {
+	case *types.PrivateEndpointMemberManagedVpcResource:
+		bs, err := marshalJSON(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"managedVpcResource": json.RawMessage(bs),
+		}, nil
+	case *types.PrivateEndpointMemberSelfManagedLatticeResource:
+		nested, err := convertFromSelfManagedLatticeResource(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"selfManagedLatticeResource": nested,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown PrivateEndpoint type: %T", v)
+	}
+}
+
+// convertToSelfManagedLatticeResource converts any value to types.SelfManagedLatticeResource
+func convertToSelfManagedLatticeResource(v any, strict bool) (types.SelfManagedLatticeResource, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var variants struct {
+
+		// ResourceConfigurationIdentifier variant
+		ResourceConfigurationIdentifier *string `json:"resourceConfigurationIdentifier"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(&variants); err != nil {
+		return nil, err
+	}
+
+	// Check resourceConfigurationIdentifier variant
+	if variants.ResourceConfigurationIdentifier != nil {
+		return &types.SelfManagedLatticeResourceMemberResourceConfigurationIdentifier{
+			Value: *variants.ResourceConfigurationIdentifier,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no valid variant found for SelfManagedLatticeResource")
+}
+
+// convertFromSelfManagedLatticeResource converts types.SelfManagedLatticeResource to map[string]any
+func convertFromSelfManagedLatticeResource(v types.SelfManagedLatticeResource) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch v := v.(type) {
+	case *types.SelfManagedLatticeResourceMemberResourceConfigurationIdentifier:
+		return map[string]any{
+			"resourceConfigurationIdentifier": v.Value,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown SelfManagedLatticeResource type: %T", v)
+	}
+}
+
 func newAgentRuntimeFromResponse(out *bedrockagentcorecontrol.GetAgentRuntimeOutput) (*AgentRuntime, error) {
 	bs, err := marshalJSON(out, func(opts *marshalJSONOptions) {
 		opts.hooks = append(opts.hooks, func(path, key string, value any) (string, any, error) {
@@ -197,6 +421,7 @@ func newAgentRuntimeFromResponse(out *bedrockagentcorecontrol.GetAgentRuntimeOut
 		})
 		opts.ignoreLowerCamelPaths = append(opts.ignoreLowerCamelPaths,
 			"$.environmentVariables.*",
+			"$.tags.*",
 		)
 	})
 	if err != nil {
@@ -242,6 +467,7 @@ func newUpdateAgentRuntimeInput(out *bedrockagentcorecontrol.GetAgentRuntimeOutp
 		})
 		opts.ignoreUpperCamelPaths = append(opts.ignoreUpperCamelPaths,
 			"$.environmentVariables.*",
+			"$.tags.*",
 		)
 	}
 	// Override with the definition file
@@ -290,6 +516,7 @@ func unmarshalAgentRuntime(bs []byte, strict bool) (*AgentRuntime, error) {
 		opts.strict = strict
 		opts.ignoreUpperCamelPaths = append(opts.ignoreUpperCamelPaths,
 			"$.environmentVariables.*",
+			"$.tags.*",
 		)
 	}); err != nil {
 		return nil, err
@@ -325,6 +552,7 @@ func marshalAgentRuntime(v *AgentRuntime, indent string) ([]byte, error) {
 		})
 		opts.ignoreLowerCamelPaths = append(opts.ignoreLowerCamelPaths,
 			"$.environmentVariables.*",
+			"$.tags.*",
 		)
 	})
 	if err != nil {
@@ -227,3 +227,123 @@ func TestMarshalUnmarshalAgentRuntime_RequestHeaderConfiguration_Generated(t *te
 		})
 	}
 }
+
+func TestConvertCode_Generated(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Input     any
+		Expected  types.Code
+		ShouldErr bool
+	}{
+		{
+			Name:      "s3",
+			Input:     map[string]any{"s3": map[string]any{"bucket": "test_value", "prefix": "test_value", "versionId": "test_value"}},
+			Expected:  &types.CodeMemberS3{Value: types.S3Location{Bucket: aws.String("test_value"), Prefix: aws.String("test_value"), VersionId: aws.String("test_value")}},
+			ShouldErr: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			// Test convertToCode
+			got, err := convertToCode(tc.Input, true)
+			if tc.ShouldErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.Expected, got)
+
+			// Test round-trip through convertFromCode
+			converted, err := convertFromCode(got)
+			require.NoError(t, err)
+
+			// Convert back again
+			roundTrip, err := convertToCode(converted, true)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.Expected, roundTrip)
+		})
+	}
+}
+
+func TestConvertPrivateEndpoint_Generated(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Input     any
+		Expected  types.PrivateEndpoint
+		ShouldErr bool
+	}{
+		{
+			Name:      "managedVpcResource",
+			Input:     map[string]any{"managedVpcResource": map[string]any{"endpointIpAddressType": "test_value", "subnetIds": []any{"test_value", "test_value"}, "vpcIdentifier": "test_value", "routingDomain": "test_value", "securityGroupIds": []any{"test_value", "test_value"}, "tags": map[string]any{"test_key": "test_value"}}},
+			Expected:  &types.PrivateEndpointMemberManagedVpcResource{Value: types.ManagedVpcResource{EndpointIpAddressType: types.EndpointIpAddressType("test_value"), SubnetIds: []string{"test_value", "test_value"}, VpcIdentifier: aws.String("test_value"), RoutingDomain: aws.String("test_value"), SecurityGroupIds: []string{"test_value", "test_value"}, Tags: map[string]string{"test_key": "test_value"}}},
+			ShouldErr: false,
+		},
+		{
+			Name:      "selfManagedLatticeResource",
+			Input:     map[string]any{"selfManagedLatticeResource": map[string]any{"resourceConfigurationIdentifier": "test_value"}},
+			Expected:  &types.PrivateEndpointMemberSelfManagedLatticeResource{Value: &types.SelfManagedLatticeResourceMemberResourceConfigurationIdentifier{Value: "test_value"}},
+			ShouldErr: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			// Test convertToPrivateEndpoint
+			got, err := convertToPrivateEndpoint(tc.Input, true)
+			if tc.ShouldErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.Expected, got)
+
+			// Test round-trip through convertFromPrivateEndpoint
+			converted, err := convertFromPrivateEndpoint(got)
+			require.NoError(t, err)
+
+			// Convert back again
+			roundTrip, err := convertToPrivateEndpoint(converted, true)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.Expected, roundTrip)
+		})
+	}
+}
+
+func TestConvertSelfManagedLatticeResource_Generated(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Input     any
+		Expected  types.SelfManagedLatticeResource
+		ShouldErr bool
+	}{
+		{
+			Name:      "resourceConfigurationIdentifier",
+			Input:     map[string]any{"resourceConfigurationIdentifier": "test_value"},
+			Expected:  &types.SelfManagedLatticeResourceMemberResourceConfigurationIdentifier{Value: "test_value"},
+			ShouldErr: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			// Test convertToSelfManagedLatticeResource
+			got, err := convertToSelfManagedLatticeResource(tc.Input, true)
+			if tc.ShouldErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.Expected, got)
+
+			// Test round-trip through convertFromSelfManagedLatticeResource
+			converted, err := convertFromSelfManagedLatticeResource(got)
+			require.NoError(t, err)
+
+			// Convert back again
+			roundTrip, err := convertToSelfManagedLatticeResource(converted, true)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.Expected, roundTrip)
+		})
+	}
+}
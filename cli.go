@@ -8,15 +8,20 @@ import (
 	"strings"
 
 	"github.com/alecthomas/kong"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/fatih/color"
 	"github.com/mashiike/slogutils"
 )
 
 type CLI struct {
 	GlobalOption
-	Color     bool   `help:"enable colored output" default:"true" env:"ACRUN_COLOR" negatable:"" json:"color,omitempty"`
-	LogLevel  string `help:"Log level" default:"info" enum:"debug,info,warn,error"`
-	LogFormat string `help:"Log format(text,json)" default:"text" enum:"text,json"`
+	Color          bool   `help:"enable colored output" default:"true" env:"ACRUN_COLOR" negatable:"" json:"color,omitempty"`
+	LogLevel       string `help:"Log level" default:"info" enum:"debug,info,warn,error"`
+	LogFormat      string `help:"Log format(text,json)" default:"text" enum:"text,json"`
+	Yes            bool   `name:"yes" aliases:"assume-yes" short:"y" help:"answer yes to all confirmations" default:"false"`
+	Config         string `name:"config" help:"project config file. default is acrun.jsonnet or acrun.json in the current directory" env:"ACRUN_CONFIG"`
+	Env            string `name:"env" help:"environment in the project config" env:"ACRUN_ENV"`
+	NonInteractive bool   `name:"non-interactive" help:"never prompt; confirmations fail unless --yes is given" env:"ACRUN_NONINTERACTIVE" default:"false"`
 
 	Init      InitOption      `cmd:"" help:"Initialize acrun configuration."`
 	Invoke    InvokeOption    `cmd:"" help:"Invoke the agent."`
@@ -25,6 +30,10 @@ type CLI struct {
 	Render    RenderOption    `cmd:"" help:"Render the agent runtime configuration."`
 	Delete    DeleteOption    `cmd:"" help:"Delete the agent runtime."`
 	Rollback  RollbackOption  `cmd:"" help:"Rollback the agent runtime to a specific version."`
+	Promote   PromoteOption   `cmd:"" help:"Promote the version of an endpoint to another endpoint."`
+	Status    StatusOption    `cmd:"" help:"Show the status of the agent runtime and its endpoints."`
+	Versions  VersionsOption  `cmd:"" help:"List versions of the agent runtime."`
+	Endpoint  EndpointOption  `cmd:"" help:"Manage endpoints of the agent runtime."`
 	ECRImages ECRImagesOption `cmd:"ecr-images" help:"List ECR image URIs used by the agent runtime."`
 	Version   struct{}        `cmd:"" help:"Show version."`
 }
@@ -66,31 +75,92 @@ func (c *CLI) Run(ctx context.Context) error {
 	default:
 		return fmt.Errorf("unknown log format: %s", c.LogFormat)
 	}
-	slog.SetDefault(logger)
 	color.NoColor = !c.Color
 
-	app, err := New(ctx, &c.GlobalOption)
+	settings, err := loadProjectSettings(c.Config, c.Env)
+	if err != nil {
+		return err
+	}
+	if settings != nil {
+		c.applyProjectSettings(settings)
+	}
+	app, err := New(ctx, &c.GlobalOption, WithLogger(logger), WithConfirmer(PromptConfirmer{
+		AssumeYes:      c.Yes,
+		NonInteractive: c.NonInteractive,
+	}))
 	if err != nil {
 		return err
 	}
 	switch strings.Split(k.Command(), " ")[0] {
 	case "init":
-		return app.Init(ctx, &c.Init)
+		_, err := app.Init(ctx, &c.Init)
+		return err
 	case "invoke":
-		return app.Invoke(ctx, &c.Invoke)
+		_, err := app.Invoke(ctx, &c.Invoke)
+		return err
 	case "diff":
-		return app.Diff(ctx, &c.Diff)
+		_, err := app.Diff(ctx, &c.Diff)
+		return err
 	case "deploy":
-		return app.Deploy(ctx, &c.Deploy)
+		_, err := app.Deploy(ctx, &c.Deploy)
+		return err
 	case "render":
-		return app.Render(ctx, &c.Render)
+		_, err := app.Render(ctx, &c.Render)
+		return err
 	case "delete":
-		return app.Delete(ctx, &c.Delete)
+		_, err := app.Delete(ctx, &c.Delete)
+		return err
 	case "rollback":
-		return app.Rollback(ctx, &c.Rollback)
+		_, err := app.Rollback(ctx, &c.Rollback)
+		return err
+	case "promote":
+		_, err := app.Promote(ctx, &c.Promote)
+		return err
+	case "status":
+		_, err := app.Status(ctx, &c.Status)
+		return err
+	case "versions":
+		if strings.HasPrefix(k.Command(), "versions show") {
+			_, err := app.VersionsShow(ctx, &c.Versions.Show)
+			return err
+		}
+		_, err := app.VersionsList(ctx, &c.Versions.List)
+		return err
+	case "endpoint":
+		switch strings.Split(k.Command(), " ")[1] {
+		case "list":
+			_, err := app.EndpointList(ctx, &c.Endpoint.List)
+			return err
+		case "create":
+			_, err := app.EndpointCreate(ctx, &c.Endpoint.Create)
+			return err
+		case "delete":
+			_, err := app.EndpointDelete(ctx, &c.Endpoint.Delete)
+			return err
+		case "describe":
+			_, err := app.EndpointDescribe(ctx, &c.Endpoint.Describe)
+			return err
+		}
+		return fmt.Errorf("unknown command: %s", k.Command())
 	case "ecr-images":
-		return app.ECRImages(ctx, &c.ECRImages)
+		_, err := app.ECRImages(ctx, &c.ECRImages)
+		return err
 	default:
 		return fmt.Errorf("unknown command: %s", k.Command())
 	}
 }
+
+// applyProjectSettings sets the project settings to the options that are not given explicitly.
+func (c *CLI) applyProjectSettings(s *ProjectSettings) {
+	s.Apply(&c.GlobalOption)
+	if s.EndpointName != "" {
+		for _, p := range []**string{&c.Deploy.EndpointName, &c.Rollback.EndpointName, &c.Invoke.EndpointName, &c.Diff.Qualifier} {
+			if *p == nil {
+				*p = aws.String(s.EndpointName)
+			}
+		}
+	}
+	if s.Ignore != "" && c.Diff.Ignore == "" {
+		c.Diff.Ignore = s.Ignore
+	}
+}
@@ -26,19 +26,29 @@ type UnionFieldInfo struct {
 	FieldName     string       // e.g., "AgentRuntimeArtifact"
 	InterfaceType string       // e.g., "types.AgentRuntimeArtifact"
 	Members       []MemberInfo // List of concrete member types
+	Nested        bool         // True if the union appears inside a member value (e.g., types.Code)
+}
+
+// NestedUnionInfo holds metadata about a union field inside a member value struct
+type NestedUnionInfo struct {
+	FieldName string // e.g., "Code"
+	JSONKey   string // e.g., "code"
+	UnionName string // e.g., "Code"
 }
 
 // MemberInfo holds metadata about a concrete union member
 type MemberInfo struct {
-	TypeName       string      // e.g., "AgentRuntimeArtifactMemberContainerConfiguration"
-	JSONKey        string      // e.g., "containerConfiguration"
-	JSONTag        string      // e.g., "`json:\"containerConfiguration\"`"
-	ValueType      string      // e.g., "github.com/.../types.ContainerConfiguration"
-	ValueTypeShort string      // e.g., "types.ContainerConfiguration"
-	ValueFields    []FieldInfo // Fields of the Value struct (if struct)
-	IsDirectValue  bool        // True if Value is not a struct (e.g., []string)
-	TestInputJSON  string      // Generated test input JSON (e.g., `{"containerConfiguration": {...}}`)
-	TestExpected   string      // Generated expected Go value (e.g., `&types.XXX{Value: ...}`)
+	TypeName       string            // e.g., "AgentRuntimeArtifactMemberContainerConfiguration"
+	JSONKey        string            // e.g., "containerConfiguration"
+	JSONTag        string            // e.g., "`json:\"containerConfiguration\"`"
+	ValueType      string            // e.g., "github.com/.../types.ContainerConfiguration"
+	ValueTypeShort string            // e.g., "types.ContainerConfiguration"
+	ValueFields    []FieldInfo       // Fields of the Value struct (if struct)
+	IsDirectValue  bool              // True if Value is not a struct (e.g., []string)
+	NestedUnions   []NestedUnionInfo // Union fields of the Value struct (e.g., CodeConfiguration.Code)
+	ValueUnion     string            // Union name if Value itself is a union (e.g., "SelfManagedLatticeResource")
+	TestInputJSON  string            // Generated test input JSON (e.g., `{"containerConfiguration": {...}}`)
+	TestExpected   string            // Generated expected Go value (e.g., `&types.XXX{Value: ...}`)
 }
 
 // FieldInfo holds metadata about a struct field
@@ -101,50 +111,40 @@ func main() {
 	for i := 0; i < struc.NumFields(); i++ {
 		field := struc.Field(i)
 		fieldType := field.Type()
-		iface, ok := fieldType.Underlying().(*types.Interface)
-		if !ok {
+		if _, ok := fieldType.Underlying().(*types.Interface); !ok {
 			// Not an interface field; skip.
 			continue
 		}
+		unionFields = append(unionFields, collectUnion(typesPkg, field.Name(), fieldType, false))
+	}
 
-		unionInfo := UnionFieldInfo{
-			FieldName:     field.Name(),
-			InterfaceType: fieldType.String(),
-			Members:       []MemberInfo{},
-		}
-
-		// Collect the concrete types in the types package that implement this interface.
-		for _, name := range typesPkg.Types.Scope().Names() {
-			obj := typesPkg.Types.Scope().Lookup(name)
-			tn, ok := obj.(*types.TypeName)
-			if !ok {
-				continue
+	// Collect unions nested in member values (e.g., CodeConfiguration.Code).
+	seen := map[string]bool{}
+	for i := 0; i < len(unionFields); i++ {
+		for _, member := range unionFields[i].Members {
+			names := []string{}
+			for _, nested := range member.NestedUnions {
+				names = append(names, nested.UnionName)
 			}
-			// Check whether the named type or its pointer implements the interface.
-			t := tn.Type()
-			// Try pointer type first; many union member wrappers are pointer receivers.
-			pt := types.NewPointer(t)
-			if types.AssignableTo(pt, fieldType) || types.Implements(pt, iface) {
-				// Skip UnknownUnionMember
-				if tn.Name() == "UnknownUnionMember" {
-					continue
-				}
-				// Skip the interface itself
-				if types.Identical(t, fieldType) {
+			if member.ValueUnion != "" {
+				names = append(names, member.ValueUnion)
+			}
+			for _, name := range names {
+				if seen[name] {
 					continue
 				}
-
-				// Extract Member type metadata
-				if strct, ok := t.Underlying().(*types.Struct); ok {
-					memberInfo := extractMemberInfo(tn.Name(), strct)
-					unionInfo.Members = append(unionInfo.Members, memberInfo)
+				seen[name] = true
+				obj := typesPkg.Types.Scope().Lookup(name)
+				if obj == nil {
+					log.Fatalf("nested union %s not found in package", name)
 				}
+				unionFields = append(unionFields, collectUnion(typesPkg, name, obj.Type(), true))
 			}
 		}
-
-		unionFields = append(unionFields, unionInfo)
 	}
 
+	fillValueUnionTestValues(unionFields)
+
 	// Generate code
 	if err := generateCode(unionFields); err != nil {
 		log.Fatalf("failed to generate code: %v", err)
@@ -158,6 +158,69 @@ func main() {
 	log.Printf("Successfully generated aws.gen.go and aws.gen_test.go")
 }
 
+// collectUnion collects the concrete member types in the types package that implement the union interface.
+func collectUnion(typesPkg *packages.Package, fieldName string, fieldType types.Type, nested bool) UnionFieldInfo {
+	iface := fieldType.Underlying().(*types.Interface)
+	unionInfo := UnionFieldInfo{
+		FieldName:     fieldName,
+		InterfaceType: fieldType.String(),
+		Members:       []MemberInfo{},
+		Nested:        nested,
+	}
+	for _, name := range typesPkg.Types.Scope().Names() {
+		obj := typesPkg.Types.Scope().Lookup(name)
+		tn, ok := obj.(*types.TypeName)
+		if !ok {
+			continue
+		}
+		// Check whether the named type or its pointer implements the interface.
+		t := tn.Type()
+		// Try pointer type first; many union member wrappers are pointer receivers.
+		pt := types.NewPointer(t)
+		if types.AssignableTo(pt, fieldType) || types.Implements(pt, iface) {
+			// Skip UnknownUnionMember
+			if tn.Name() == "UnknownUnionMember" {
+				continue
+			}
+			// Skip the interface itself
+			if types.Identical(t, fieldType) {
+				continue
+			}
+
+			// Extract Member type metadata
+			if strct, ok := t.Underlying().(*types.Struct); ok {
+				memberInfo := extractMemberInfo(tn.Name(), strct)
+				unionInfo.Members = append(unionInfo.Members, memberInfo)
+			}
+		}
+	}
+	return unionInfo
+}
+
+// fillValueUnionTestValues generates test values for members whose Value is a union,
+// using the first member of the nested union.
+func fillValueUnionTestValues(unionFields []UnionFieldInfo) {
+	byName := map[string]UnionFieldInfo{}
+	for _, u := range unionFields {
+		byName[u.FieldName] = u
+	}
+	for i := range unionFields {
+		for j := range unionFields[i].Members {
+			member := &unionFields[i].Members[j]
+			if member.ValueUnion == "" {
+				continue
+			}
+			nested, ok := byName[member.ValueUnion]
+			if !ok || len(nested.Members) == 0 {
+				log.Fatalf("nested union %s has no members", member.ValueUnion)
+			}
+			first := nested.Members[0]
+			member.TestInputJSON = fmt.Sprintf(`map[string]any{"%s": %s}`, member.JSONKey, first.TestInputJSON)
+			member.TestExpected = fmt.Sprintf(`&types.%s{Value: %s}`, member.TypeName, first.TestExpected)
+		}
+	}
+}
+
 func generateCode(unionFields []UnionFieldInfo) error {
 	// Parse template
 	tmpl, err := template.New("codegen").Funcs(template.FuncMap{
@@ -165,6 +228,7 @@ func generateCode(unionFields []UnionFieldInfo) error {
 			return toFieldName(toPascalCase(s))
 		},
 		"toLowerCamel": toLowerCamelCase,
+		"trimPackage":  trimPackage,
 	}).Parse(templateContent)
 	if err != nil {
 		return fmt.Errorf("failed to parse template: %w", err)
@@ -248,6 +312,14 @@ func extractMemberInfo(typeName string, strct *types.Struct) MemberInfo {
 			member.ValueType = field.Type().String()
 			member.ValueTypeShort = shortTypeName(field.Type().String())
 
+			// Check if Value is a union
+			if named, ok := field.Type().(*types.Named); ok {
+				if _, ok := named.Underlying().(*types.Interface); ok {
+					member.ValueUnion = named.Obj().Name()
+					// Test values are filled in by fillValueUnionTestValues
+					break
+				}
+			}
 			// Check if Value is a struct
 			if valueStruct, ok := field.Type().Underlying().(*types.Struct); ok {
 				member.IsDirectValue = false
@@ -265,6 +337,16 @@ func extractMemberInfo(typeName string, strct *types.Struct) MemberInfo {
 					if _, ok := vField.Type().(*types.Pointer); ok {
 						fieldInfo.IsPointer = true
 					}
+					// Check if it's a nested union type
+					if named, ok := vField.Type().(*types.Named); ok {
+						if _, ok := named.Underlying().(*types.Interface); ok {
+							member.NestedUnions = append(member.NestedUnions, NestedUnionInfo{
+								FieldName: vField.Name(),
+								JSONKey:   toLowerCamelCase(vField.Name()),
+								UnionName: named.Obj().Name(),
+							})
+						}
+					}
 					member.ValueFields = append(member.ValueFields, fieldInfo)
 				}
 				// Generate test values for struct
@@ -290,6 +372,15 @@ func shortTypeName(fullType string) string {
 	return strings.ReplaceAll(fullType, "github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types.", "types.")
 }
 
+// trimPackage removes the package qualifier from a short type name
+// e.g., "types.CodeConfiguration" -> "CodeConfiguration"
+func trimPackage(typeName string) string {
+	if i := strings.LastIndex(typeName, "."); i >= 0 {
+		return typeName[i+1:]
+	}
+	return typeName
+}
+
 // inferJSONKey derives the JSON key from the Member type name
 // e.g., "AgentRuntimeArtifactMemberContainerConfiguration" -> "containerConfiguration"
 // e.g., "RequestHeaderConfigurationMemberRequestHeaderAllowlist" -> "allowList"
@@ -427,6 +518,9 @@ func generateTestValueJSON(t types.Type) string {
 	case *types.Slice:
 		elem := generateTestValueJSON(u.Elem())
 		return fmt.Sprintf(`[]any{%s, %s}`, elem, elem)
+	case *types.Map:
+		elem := generateTestValueJSON(u.Elem())
+		return fmt.Sprintf(`map[string]any{"test_key": %s}`, elem)
 	case *types.Basic:
 		switch u.Kind() {
 		case types.String:
@@ -490,6 +584,11 @@ func generateTestValueGo(t types.Type) string {
 		elemType := typeStringShort(u.Elem())
 		elem := generateTestValueGo(u.Elem())
 		return fmt.Sprintf(`[]%s{%s, %s}`, elemType, elem, elem)
+	case *types.Map:
+		keyType := typeStringShort(u.Key())
+		elemType := typeStringShort(u.Elem())
+		elem := generateTestValueGo(u.Elem())
+		return fmt.Sprintf(`map[%s]%s{"test_key": %s}`, keyType, elemType, elem)
 	case *types.Basic:
 		switch u.Kind() {
 		case types.String:
@@ -0,0 +1,57 @@
+package acrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Songmu/prompter"
+	"github.com/mattn/go-isatty"
+)
+
+// ErrConfirmationRequired is returned when an operation requires a confirmation but no Confirmer is set.
+var ErrConfirmationRequired = errors.New("confirmation required")
+
+// Confirmer confirms destructive operations, such as deleting resources or overwriting files.
+type Confirmer interface {
+	// Confirm returns true if the operation described by message may proceed.
+	Confirm(ctx context.Context, message string) (bool, error)
+}
+
+// ConfirmerFunc is an adapter to use a function as a Confirmer.
+type ConfirmerFunc func(ctx context.Context, message string) (bool, error)
+
+func (f ConfirmerFunc) Confirm(ctx context.Context, message string) (bool, error) {
+	return f(ctx, message)
+}
+
+// PromptConfirmer asks the user on the terminal.
+// When stdin is not a terminal, it fails with ErrConfirmationRequired instead of assuming "no".
+type PromptConfirmer struct {
+	// AssumeYes answers "yes" to all confirmations without prompting.
+	AssumeYes bool
+	// NonInteractive never prompts, even if stdin is a terminal.
+	NonInteractive bool
+}
+
+func (c PromptConfirmer) Confirm(_ context.Context, message string) (bool, error) {
+	if c.AssumeYes {
+		return true, nil
+	}
+	if c.NonInteractive {
+		return false, fmt.Errorf("%w: %q: non-interactive mode, use --yes to proceed", ErrConfirmationRequired, message)
+	}
+	if !isatty.IsTerminal(os.Stdin.Fd()) && !isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+		return false, fmt.Errorf("%w: %q: stdin is not a terminal, use --yes to proceed", ErrConfirmationRequired, message)
+	}
+	return prompter.YN(message, false), nil
+}
+
+// confirm asks the configured Confirmer.
+func (app *App) confirm(ctx context.Context, message string) (bool, error) {
+	if app.confirmer == nil {
+		return false, ErrConfirmationRequired
+	}
+	return app.confirmer.Confirm(ctx, message)
+}
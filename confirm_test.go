@@ -0,0 +1,84 @@
+package acrun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newConfirmTestApp(t *testing.T, ctrl *gomock.Controller, appOpts ...Option) (*App, *bytes.Buffer) {
+	t.Helper()
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+			AgentRuntimes: []types.AgentRuntime{
+				{
+					AgentRuntimeId:   aws.String("test-runtime-id"),
+					AgentRuntimeName: aws.String("hosted_agent_dummy"),
+					AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+				},
+			},
+		}, nil).
+		AnyTimes()
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+		aws.Config{},
+		mockCtrlClient,
+		NewMockBedrockAgentCoreClient(ctrl),
+		NewMockECRClient(ctrl),
+		NewMockSTSClient(ctrl),
+		NewMockS3Client(ctrl),
+		appOpts...,
+	)
+	require.NoError(t, err)
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+	return app, &stdout
+}
+
+func TestDelete_ConfirmationRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	app, _ := newConfirmTestApp(t, ctrl)
+	_, err := app.Delete(context.Background(), &DeleteOption{})
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrConfirmationRequired), err.Error())
+}
+
+func TestDelete_ConfirmerDeclined(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var asked string
+	app, _ := newConfirmTestApp(t, ctrl, WithConfirmer(ConfirmerFunc(func(_ context.Context, message string) (bool, error) {
+		asked = message
+		return false, nil
+	})))
+	result, err := app.Delete(context.Background(), &DeleteOption{})
+	require.NoError(t, err)
+	require.Contains(t, asked, "hosted_agent_dummy")
+	require.False(t, result.Deleted)
+	require.Empty(t, result.Endpoints)
+}
+
+func TestPromptConfirmer(t *testing.T) {
+	ok, err := PromptConfirmer{AssumeYes: true, NonInteractive: true}.Confirm(context.Background(), "delete?")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = PromptConfirmer{NonInteractive: true}.Confirm(context.Background(), "delete?")
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrConfirmationRequired), err.Error())
+	require.Contains(t, err.Error(), "--yes")
+	require.False(t, ok)
+}
@@ -20,6 +20,7 @@ func TestDelete(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Setup expectations
 	mockCtrlClient.EXPECT().
@@ -74,6 +75,7 @@ func TestDelete(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -85,7 +87,7 @@ func TestDelete(t *testing.T) {
 		Force:  true, // Skip confirmation
 	}
 
-	err = app.Delete(context.Background(), opt)
+	_, err = app.Delete(context.Background(), opt)
 	require.NoError(t, err)
 }
 
@@ -97,6 +99,7 @@ func TestDelete_NotFound(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Setup expectations - no runtime found
 	mockCtrlClient.EXPECT().
@@ -113,6 +116,7 @@ func TestDelete_NotFound(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 	var stdout, stderr bytes.Buffer
@@ -123,7 +127,7 @@ func TestDelete_NotFound(t *testing.T) {
 		Force:  true,
 	}
 
-	err = app.Delete(context.Background(), opt)
+	_, err = app.Delete(context.Background(), opt)
 	require.NoError(t, err) // Should succeed even if not found
 }
 
@@ -135,6 +139,7 @@ func TestDelete_DryRun(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Setup expectations
 	mockCtrlClient.EXPECT().
@@ -172,6 +177,7 @@ func TestDelete_DryRun(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 	var stdout, stderr bytes.Buffer
@@ -182,6 +188,6 @@ func TestDelete_DryRun(t *testing.T) {
 		Force:  true,
 	}
 
-	err = app.Delete(context.Background(), opt)
+	_, err = app.Delete(context.Background(), opt)
 	require.NoError(t, err)
 }
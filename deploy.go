@@ -4,7 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -17,44 +18,101 @@ type DeployOption struct {
 	EndpointName    *string       `name:"endpoint-name" help:"the endpoint name to deploy. if not specified, use the default endpoint."`
 	WaitDuration    time.Duration `name:"wait-duration" help:"maximum duration to wait until the agent runtime is ready" default:"30m"`
 	PollingInterval time.Duration `name:"polling-interval" help:"polling interval to check the agent runtime status" default:"5s"`
+
+	NoWaitEndpoint       bool          `name:"no-wait-endpoint" help:"do not wait until the endpoint is ready and serves the deployed version" default:"false"`
+	EndpointWaitDuration time.Duration `name:"endpoint-wait-duration" help:"maximum duration to wait until the endpoint serves the deployed version" default:"10m"`
+
+	ForceNewVersion bool `name:"force-new-version" help:"create a new version even if the local configuration has no differences from the remote" default:"false"`
+
+	Plan string `name:"plan" help:"apply the plan file written by diff --out instead of evaluating the agent runtime file" type:"path"`
+
+	SmokeTest string `name:"smoke-test" help:"smoke test cases file (json or jsonnet). if any case fails, the endpoint is rolled back to the previous version" type:"path"`
+
+	Prune bool `name:"prune" help:"delete endpoints that exist remotely but are not declared in the endpoints section" default:"false"`
+
+	Output      string `name:"output" short:"o" help:"output format (text, json)" default:"text" enum:"text,json"`
+	OutputsFile string `name:"outputs-file" help:"append the result as key=value lines to the file, e.g. $GITHUB_OUTPUT" type:"path"`
 }
 
-func (app *App) Deploy(ctx context.Context, opt *DeployOption) error {
+func (app *App) Deploy(ctx context.Context, opt *DeployOption) (*DeployResult, error) {
+	var plan *Plan
+	var planned *AgentRuntime
+	if opt.Plan != "" {
+		var err error
+		plan, planned, err = app.loadPlan(ctx, opt.Plan)
+		if err != nil {
+			return nil, fmt.Errorf("load plan: %w", err)
+		}
+		if opt.EndpointName == nil || *opt.EndpointName == "" {
+			opt.EndpointName = aws.String(plan.EndpointName)
+		} else if *opt.EndpointName != plan.EndpointName {
+			return nil, fmt.Errorf("endpoint %s does not match the planned endpoint %s", *opt.EndpointName, plan.EndpointName)
+		}
+	}
 	e := fillEndpointName(opt.EndpointName)
 	if e == DefaultEndpointName {
-		return errors.New("deploying to the DEFAULT endpoint is not allowed")
+		return nil, errors.New("deploying to the DEFAULT endpoint is not allowed")
 	}
 	opt.EndpointName = &e
 	if opt.DryRun {
-		slog.WarnContext(ctx, "starting deploy in DRY RUN mode. No changes will be made.")
-		defer slog.WarnContext(ctx, "ended deploy in DRY RUN mode. No changes were made.")
+		app.logger.WarnContext(ctx, "starting deploy in DRY RUN mode. No changes will be made.")
+		defer app.logger.WarnContext(ctx, "ended deploy in DRY RUN mode. No changes were made.")
 	}
-	agentRuntime, err := app.loadAgentRuntimeFile(ctx)
+	var timer phaseTimer
+	end := timer.start("load")
+	agentRuntime, endpoints, err := app.loadDeployInput(ctx, plan, planned, opt)
 	if err != nil {
-		return fmt.Errorf("load agent runtime file: %w", err)
+		return nil, err
 	}
+	var smokeTestCases []SmokeTestCase
+	if opt.SmokeTest != "" {
+		smokeTestCases, err = app.loadSmokeTestCases(opt.SmokeTest)
+		if err != nil {
+			return nil, fmt.Errorf("load smoke test cases: %w", err)
+		}
+	}
+	end()
+	result := &DeployResult{
+		AgentRuntimeName: aws.ToString(agentRuntime.AgentRuntimeName),
+		EndpointName:     *opt.EndpointName,
+		DryRun:           opt.DryRun,
+	}
+
+	end = timer.start("update_runtime")
 	var version string
 	id, err := app.GetAgentRuntimeIDByName(ctx, *agentRuntime.AgentRuntimeName)
 	if err != nil {
 		if !errors.Is(err, ErrAgentRuntimeNotFound) {
-			return fmt.Errorf("get agent runtime ID by name: %w", err)
+			return nil, fmt.Errorf("get agent runtime ID by name: %w", err)
 		}
+		result.Created = true
+		result.NewVersion = true
 		id, version, err = app.createRuntimeAgent(ctx, agentRuntime, opt)
 		if err != nil {
-			return fmt.Errorf("createRuntimeAgent: %w", err)
+			return nil, fmt.Errorf("createRuntimeAgent: %w", err)
 		}
 	} else {
-		version, err = app.updateRuntimeAgent(ctx, agentRuntime, opt)
+		version, result.NewVersion, err = app.updateRuntimeAgent(ctx, agentRuntime, opt)
 		if err != nil {
-			return fmt.Errorf("updateRuntimeAgent: %w", err)
+			return nil, fmt.Errorf("updateRuntimeAgent: %w", err)
 		}
 	}
+	end()
+	result.AgentRuntimeID = id
+	result.Version = version
 	if !opt.DryRun {
+		end = timer.start("wait_runtime")
 		waiter := &Waiter{
+			Logger:        app.logger,
 			MaxDuration:   opt.WaitDuration,
 			CheckInterval: opt.PollingInterval,
 			LogMessage:    "waiting for agent runtime to be ready",
 			LogAttributes: []any{"id", id, "version", version},
+			Resource:      fmt.Sprintf("agent runtime %s version %s", id, version),
+			TerminalStatuses: []string{
+				string(types.AgentRuntimeStatusCreateFailed),
+				string(types.AgentRuntimeStatusUpdateFailed),
+			},
 			Checker: func(ctx context.Context) ([]any, bool, error) {
 				out, err := app.ctrlClient.GetAgentRuntime(ctx, &bedrockagentcorecontrol.GetAgentRuntimeInput{
 					AgentRuntimeId:      aws.String(id),
@@ -63,28 +121,201 @@ func (app *App) Deploy(ctx context.Context, opt *DeployOption) error {
 				if err != nil {
 					return nil, false, fmt.Errorf("GetAgentRuntime: %w", err)
 				}
-				if out.Status == types.AgentRuntimeStatusReady {
-					return []any{"status", out.Status}, true, nil
-				}
-				return []any{"status", out.Status}, false, nil
+				result.AgentRuntimeArn = aws.ToString(out.AgentRuntimeArn)
+				attrs := []any{"status", out.Status, "reason", aws.ToString(out.FailureReason)}
+				return attrs, out.Status == types.AgentRuntimeStatusReady, nil
 			},
 		}
 		if err := waiter.Wait(ctx); err != nil {
-			return fmt.Errorf("waiter.Wait: %w", err)
+			return nil, fmt.Errorf("waiter.Wait: %w", err)
+		}
+		end()
+	}
+	app.logger.InfoContext(ctx, "deployed agent runtime", "name", aws.ToString(agentRuntime.AgentRuntimeName), "id", id, "version", version)
+	end = timer.start("switch_endpoint")
+	previousVersion, err := app.createOrUpdateAgentRuntimeEndpoint(ctx, id, *opt.EndpointName, version, opt.DryRun)
+	if err != nil {
+		return nil, fmt.Errorf("createOrUpdateAgentRuntimeEndpoint: %w", err)
+	}
+	end()
+	result.PreviousVersion = previousVersion
+	if opt.DryRun {
+		if result.Created {
+			// the agent runtime does not exist, so there are no remote endpoints yet
+			id = ""
+		}
+	} else {
+		// smoke test must run against the new version, so wait for the endpoint regardless of --no-wait-endpoint
+		if !opt.NoWaitEndpoint || len(smokeTestCases) > 0 {
+			end = timer.start("wait_endpoint")
+			if err := app.waitForAgentRuntimeEndpoint(ctx, id, *opt.EndpointName, version, opt.EndpointWaitDuration, opt.PollingInterval); err != nil {
+				return nil, fmt.Errorf("wait for agent runtime endpoint: %w", err)
+			}
+			end()
+		}
+		if len(smokeTestCases) > 0 {
+			end = timer.start("smoke_test")
+			if err := app.smokeTestOrRollback(ctx, agentRuntime, id, version, previousVersion, smokeTestCases, opt); err != nil {
+				return nil, err
+			}
+			end()
+		}
+	}
+	end = timer.start("reconcile")
+	if err := app.deployEndpoints(ctx, id, endpoints, version, opt); err != nil {
+		return nil, err
+	}
+	if err := app.deployTags(ctx, id, agentRuntime, endpoints, opt); err != nil {
+		return nil, err
+	}
+	end()
+	result.Phases = timer.phases
+
+	if opt.OutputsFile != "" {
+		if err := appendOutputsFile(opt.OutputsFile, result.outputs()); err != nil {
+			return nil, err
+		}
+	}
+	return result, app.writeResult(opt.Output, result)
+}
+
+// deployTags reconciles the tags of the agent runtime and the endpoints that deploy manages, if tags are declared.
+// id is empty when the agent runtime does not exist yet in dry run mode.
+func (app *App) deployTags(ctx context.Context, id string, agentRuntime *AgentRuntime, endpoints map[string]*EndpointConfig, opt *DeployOption) error {
+	if agentRuntime.Tags == nil || id == "" {
+		return nil
+	}
+	arn, err := app.GetAgentRuntimeARNByName(ctx, *agentRuntime.AgentRuntimeName)
+	if err != nil {
+		return fmt.Errorf("get agent runtime ARN by name: %w", err)
+	}
+	if err := app.reconcileTags(ctx, arn, agentRuntime.Tags, opt.DryRun); err != nil {
+		return fmt.Errorf("reconcile agent runtime tags: %w", err)
+	}
+	names := []string{*opt.EndpointName}
+	for _, name := range slices.Sorted(maps.Keys(endpoints)) {
+		if name != *opt.EndpointName {
+			names = append(names, name)
+		}
+	}
+	if err := app.reconcileEndpointTags(ctx, id, names, agentRuntime.Tags, opt.DryRun); err != nil {
+		return fmt.Errorf("reconcile endpoint tags: %w", err)
+	}
+	return nil
+}
+
+// deployEndpoints reconciles the endpoints declared in the agent runtime file, if any.
+func (app *App) deployEndpoints(ctx context.Context, id string, endpoints map[string]*EndpointConfig, version string, opt *DeployOption) error {
+	if endpoints == nil {
+		return nil
+	}
+	actions, err := app.planEndpoints(ctx, id, endpoints, version, *opt.EndpointName, opt.Prune)
+	if err != nil {
+		return fmt.Errorf("plan endpoints: %w", err)
+	}
+	if err := app.reconcileEndpoints(ctx, id, actions, opt.DryRun, !opt.NoWaitEndpoint, opt.EndpointWaitDuration, opt.PollingInterval); err != nil {
+		return fmt.Errorf("reconcile endpoints: %w", err)
+	}
+	return nil
+}
+
+// loadDeployInput returns the agent runtime to deploy and the declared endpoints, from the plan if given, otherwise from the agent runtime file.
+// The code source is packaged and uploaded unless in dry run mode.
+func (app *App) loadDeployInput(ctx context.Context, plan *Plan, planned *AgentRuntime, opt *DeployOption) (*AgentRuntime, map[string]*EndpointConfig, error) {
+	if plan != nil {
+		if err := app.checkPlan(ctx, plan, planned); err != nil {
+			return nil, nil, err
 		}
+		if plan.CodeSource != nil && !opt.DryRun {
+			if err := app.uploadPackagedCode(ctx, planned, plan.CodeSource); err != nil {
+				return nil, nil, fmt.Errorf("package code: %w", err)
+			}
+		}
+		return planned, plan.Endpoints, nil
+	}
+	agentRuntime, ext, err := app.loadAgentRuntimeFileWithExtension(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load agent runtime file: %w", err)
 	}
-	slog.InfoContext(ctx, "deployed agent runtime", "name", aws.ToString(agentRuntime.AgentRuntimeName), "id", id, "version", version)
-	if err := app.createOrUpdateAgentRuntimeEndpoint(ctx, id, *opt.EndpointName, version, opt); err != nil {
-		return fmt.Errorf("createOrUpdateAgentRuntimeEndpoint: %w", err)
+	if ext.CodeSource != nil {
+		if err := app.packageCode(ctx, agentRuntime, ext.CodeSource, !opt.DryRun); err != nil {
+			return nil, nil, fmt.Errorf("package code: %w", err)
+		}
 	}
+	return agentRuntime, ext.Endpoints, nil
+}
+
+func (app *App) smokeTestOrRollback(ctx context.Context, agentRuntime *AgentRuntime, id string, version string, previousVersion string, cases []SmokeTestCase, opt *DeployOption) error {
+	arn, err := app.GetAgentRuntimeARNByName(ctx, *agentRuntime.AgentRuntimeName)
+	if err != nil {
+		return fmt.Errorf("get agent runtime ARN by name: %w", err)
+	}
+	app.logger.InfoContext(ctx, "running smoke test", "endpoint", *opt.EndpointName, "cases", len(cases))
+	testErr := app.runSmokeTest(ctx, arn, *opt.EndpointName, cases)
+	if testErr == nil {
+		app.logger.InfoContext(ctx, "smoke test passed", "endpoint", *opt.EndpointName)
+		return nil
+	}
+	if previousVersion == "" || previousVersion == version {
+		app.logger.WarnContext(ctx, "no previous version to roll back to", "endpoint", *opt.EndpointName)
+		return fmt.Errorf("%w: %w", ErrSmokeTestFailed, testErr)
+	}
+	app.logger.WarnContext(ctx, "smoke test failed, rolling back endpoint", "endpoint", *opt.EndpointName, "id", id, "version", previousVersion)
+	// roll back with the id and version at hand; the agent runtime file is not loaded again, as it may differ from the plan
+	current, err := app.ctrlClient.GetAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+		AgentRuntimeId: aws.String(id),
+		EndpointName:   opt.EndpointName,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w, and rollback failed: GetAgentRuntimeEndpoint: %w", ErrSmokeTestFailed, testErr, err)
+	}
+	waitDuration := opt.EndpointWaitDuration
+	if opt.NoWaitEndpoint {
+		waitDuration = 0
+	}
+	if err := app.rollbackEndpoint(ctx, id, *opt.EndpointName, previousVersion, current.Description, waitDuration, opt.PollingInterval); err != nil {
+		return fmt.Errorf("%w: %w, and rollback failed: %w", ErrSmokeTestFailed, testErr, err)
+	}
+	return fmt.Errorf("%w: %w; rolled back endpoint %s to version %s", ErrSmokeTestFailed, testErr, *opt.EndpointName, previousVersion)
+}
+
+// waitForAgentRuntimeEndpoint waits until the endpoint is READY and its live version is the specified version.
+func (app *App) waitForAgentRuntimeEndpoint(ctx context.Context, id string, endpointName string, version string, maxDuration, interval time.Duration) error {
+	waiter := &Waiter{
+		Logger:        app.logger,
+		MaxDuration:   maxDuration,
+		CheckInterval: interval,
+		LogMessage:    "waiting for agent runtime endpoint to serve the version",
+		LogAttributes: []any{"id", id, "endpoint", endpointName, "version", version},
+		Resource:      fmt.Sprintf("agent runtime endpoint %s", endpointName),
+		TerminalStatuses: []string{
+			string(types.AgentRuntimeEndpointStatusCreateFailed),
+			string(types.AgentRuntimeEndpointStatusUpdateFailed),
+		},
+		Checker: func(ctx context.Context) ([]any, bool, error) {
+			out, err := app.ctrlClient.GetAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+				AgentRuntimeId: aws.String(id),
+				EndpointName:   aws.String(endpointName),
+			})
+			if err != nil {
+				return nil, false, fmt.Errorf("GetAgentRuntimeEndpoint: %w", err)
+			}
+			attrs := []any{"status", out.Status, "live_version", aws.ToString(out.LiveVersion), "reason", aws.ToString(out.FailureReason)}
+			return attrs, out.Status == types.AgentRuntimeEndpointStatusReady && aws.ToString(out.LiveVersion) == version, nil
+		},
+	}
+	if err := waiter.Wait(ctx); err != nil {
+		return fmt.Errorf("waiter.Wait: %w", err)
+	}
+	app.logger.InfoContext(ctx, "agent runtime endpoint is ready", "endpoint", endpointName, "version", version)
 	return nil
 }
 
 func (app *App) createRuntimeAgent(ctx context.Context, agentRuntime *AgentRuntime, opt *DeployOption) (string, string, error) {
-	slog.InfoContext(ctx, "creating agent runtime", "name", aws.ToString(agentRuntime.AgentRuntimeName))
+	app.logger.InfoContext(ctx, "creating agent runtime", "name", aws.ToString(agentRuntime.AgentRuntimeName))
 	app.DumpIfVerbose(ctx, "CreateAgentRuntimeInput", agentRuntime)
 	if opt.DryRun {
-		slog.DebugContext(ctx, "dry run: create agent runtime skipped")
+		app.logger.DebugContext(ctx, "dry run: create agent runtime skipped")
 		return "(known after deploy)", "(known after deploy)", nil
 	}
 	resp, err := app.ctrlClient.CreateAgentRuntime(ctx, agentRuntime)
@@ -96,7 +327,7 @@ func (app *App) createRuntimeAgent(ctx context.Context, agentRuntime *AgentRunti
 		workloadIdentityARN = resp.WorkloadIdentityDetails.WorkloadIdentityArn
 	}
 
-	slog.DebugContext(ctx, "created agent runtime",
+	app.logger.DebugContext(ctx, "created agent runtime",
 		"arn", aws.ToString(resp.AgentRuntimeArn),
 		"version", aws.ToString(resp.AgentRuntimeVersion),
 		"id", aws.ToString(resp.AgentRuntimeId),
@@ -105,43 +336,77 @@ func (app *App) createRuntimeAgent(ctx context.Context, agentRuntime *AgentRunti
 	return aws.ToString(resp.AgentRuntimeId), aws.ToString(resp.AgentRuntimeVersion), nil
 }
 
-func (app *App) updateRuntimeAgent(ctx context.Context, agentRuntime *AgentRuntime, opt *DeployOption) (string, error) {
-	out, err := app.GetAgentRuntime(ctx, agentRuntime.AgentRuntimeName, opt.EndpointName)
+// updateRuntimeAgent updates the agent runtime and returns the version to deploy.
+// It also reports whether a new version was created.
+func (app *App) updateRuntimeAgent(ctx context.Context, agentRuntime *AgentRuntime, opt *DeployOption) (string, bool, error) {
+	out, err := app.getDeployBase(ctx, agentRuntime.AgentRuntimeName, opt.EndpointName)
 	if err != nil {
-		if !errors.Is(err, ErrAgentRuntimeNotFound) {
-			return "", fmt.Errorf("get remote agent(endpoint=%s) : %w", aws.ToString(opt.EndpointName), err)
+		return "", false, err
+	}
+	if !opt.ForceNewVersion {
+		remote, err := newAgentRuntimeFromResponse(out)
+		if err != nil {
+			return "", false, fmt.Errorf("newAgentRuntimeFromResponse: %w", err)
 		}
-		// fallback to DEFAULT
-		out, err = app.GetAgentRuntime(ctx, agentRuntime.AgentRuntimeName, aws.String(DefaultEndpointName))
+		// tags are reconciled separately and do not need a new version
+		defaults, err := app.serverDefaults(ctx)
+		if err != nil {
+			return "", false, err
+		}
+		diff, err := diffAgentRuntime(withoutTags(remote), withoutTags(agentRuntime), aws.ToString(out.AgentRuntimeArn), app.agentRuntimeFilepath, defaults)
 		if err != nil {
-			return "", fmt.Errorf("get remote agent runtime(endpoint=%s): %w", DefaultEndpointName, err)
+			return "", false, err
+		}
+		if diff == "" {
+			app.logger.InfoContext(ctx, "no differences found, skip creating a new version",
+				"name", aws.ToString(agentRuntime.AgentRuntimeName),
+				"version", aws.ToString(out.AgentRuntimeVersion),
+			)
+			return aws.ToString(out.AgentRuntimeVersion), false, nil
 		}
 	}
-	slog.InfoContext(ctx, "updating agent runtime", "name", aws.ToString(agentRuntime.AgentRuntimeName), "arn", aws.ToString(out.AgentRuntimeArn))
+	app.logger.InfoContext(ctx, "updating agent runtime", "name", aws.ToString(agentRuntime.AgentRuntimeName), "arn", aws.ToString(out.AgentRuntimeArn))
 	input, err := newUpdateAgentRuntimeInput(out, agentRuntime)
 	if err != nil {
-		return "", fmt.Errorf("newUpdateAgentRuntimeInput: %w", err)
+		return "", false, fmt.Errorf("newUpdateAgentRuntimeInput: %w", err)
 	}
 	app.DumpIfVerbose(ctx, "UpdateAgentRuntimeInput", input)
 	if opt.DryRun {
-		slog.DebugContext(ctx, "dry run: update agent runtime skipped")
-		return "(known after deploy)", nil
+		app.logger.DebugContext(ctx, "dry run: update agent runtime skipped")
+		return "(known after deploy)", true, nil
 	}
 	resp, err := app.ctrlClient.UpdateAgentRuntime(ctx, input)
 	if err != nil {
-		return "", fmt.Errorf("UpdateAgentRuntime: %w", err)
+		return "", false, fmt.Errorf("UpdateAgentRuntime: %w", err)
 	}
 	var workloadIdentityARN *string
 	if resp.WorkloadIdentityDetails != nil {
 		workloadIdentityARN = resp.WorkloadIdentityDetails.WorkloadIdentityArn
 	}
-	slog.DebugContext(ctx, "updated agent runtime",
+	app.logger.DebugContext(ctx, "updated agent runtime",
 		"arn", aws.ToString(resp.AgentRuntimeArn),
 		"version", aws.ToString(resp.AgentRuntimeVersion),
 		"id", aws.ToString(resp.AgentRuntimeId),
 		"workloadIdentityARN", aws.ToString(workloadIdentityARN),
 	)
-	return aws.ToString(resp.AgentRuntimeVersion), nil
+	return aws.ToString(resp.AgentRuntimeVersion), true, nil
+}
+
+// getDeployBase returns the remote agent runtime that deploy updates from.
+// It falls back to the DEFAULT endpoint when the endpoint does not exist yet.
+func (app *App) getDeployBase(ctx context.Context, name *string, endpointName *string) (*bedrockagentcorecontrol.GetAgentRuntimeOutput, error) {
+	out, err := app.GetAgentRuntime(ctx, name, endpointName)
+	if err != nil {
+		if !errors.Is(err, ErrAgentRuntimeNotFound) {
+			return nil, fmt.Errorf("get remote agent(endpoint=%s) : %w", aws.ToString(endpointName), err)
+		}
+		// fallback to DEFAULT
+		out, err = app.GetAgentRuntime(ctx, name, aws.String(DefaultEndpointName))
+		if err != nil {
+			return nil, fmt.Errorf("get remote agent runtime(endpoint=%s): %w", DefaultEndpointName, err)
+		}
+	}
+	return out, nil
 }
 
 func coalesce[T any](args ...*T) *T {
@@ -153,20 +418,23 @@ func coalesce[T any](args ...*T) *T {
 	return nil
 }
 
-func (app *App) createOrUpdateAgentRuntimeEndpoint(ctx context.Context, id string, endpointName string, version string, opt *DeployOption) error {
+// createOrUpdateAgentRuntimeEndpoint points the endpoint to the version, creating the endpoint if it does not exist.
+// It returns the version the endpoint pointed to before the update, or an empty string when the endpoint was created.
+func (app *App) createOrUpdateAgentRuntimeEndpoint(ctx context.Context, id string, endpointName string, version string, dryRun bool) (string, error) {
+	var previousVersion string
 	if current, err := app.ctrlClient.GetAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
 		AgentRuntimeId: aws.String(id),
-		EndpointName:   opt.EndpointName,
+		EndpointName:   aws.String(endpointName),
 	}); err != nil {
 		var nfe *types.ResourceNotFoundException
 		var ade *types.AccessDeniedException
 		if !errors.As(err, &nfe) && !errors.As(err, &ade) {
-			return fmt.Errorf("get agent runtime endpoint: %w", ErrAgentRuntimeNotFound)
+			return "", fmt.Errorf("get agent runtime endpoint: %w", ErrAgentRuntimeNotFound)
 		}
-		slog.InfoContext(ctx, "creating agent runtime endpoint", "name", endpointName, "version", version)
-		if opt.DryRun {
-			slog.DebugContext(ctx, "dry run: create agent runtime endpoint skipped")
-			return nil
+		app.logger.InfoContext(ctx, "creating agent runtime endpoint", "name", endpointName, "version", version)
+		if dryRun {
+			app.logger.DebugContext(ctx, "dry run: create agent runtime endpoint skipped")
+			return "", nil
 		}
 		resp, err := app.ctrlClient.CreateAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.CreateAgentRuntimeEndpointInput{
 			AgentRuntimeId:      aws.String(id),
@@ -175,14 +443,19 @@ func (app *App) createOrUpdateAgentRuntimeEndpoint(ctx context.Context, id strin
 			Description:         aws.String(fmt.Sprintf("Managed by %s", AppName)),
 		})
 		if err != nil {
-			return fmt.Errorf("CreateAgentRuntimeEndpoint: %w", err)
+			return "", fmt.Errorf("CreateAgentRuntimeEndpoint: %w", err)
 		}
-		slog.DebugContext(ctx, "created agent runtime endpoint", "name", endpointName, "arn", aws.ToString(resp.AgentRuntimeEndpointArn))
+		app.logger.DebugContext(ctx, "created agent runtime endpoint", "name", endpointName, "arn", aws.ToString(resp.AgentRuntimeEndpointArn))
 	} else {
-		slog.InfoContext(ctx, "updating agent runtime endpoint", "name", endpointName, "version", version)
-		if opt.DryRun {
-			slog.DebugContext(ctx, "dry run: update agent runtime endpoint skipped")
-			return nil
+		previousVersion = aws.ToString(coalesce(current.TargetVersion, current.LiveVersion))
+		if previousVersion == version {
+			app.logger.InfoContext(ctx, "agent runtime endpoint already points to the version", "name", endpointName, "version", version)
+			return previousVersion, nil
+		}
+		app.logger.InfoContext(ctx, "updating agent runtime endpoint", "name", endpointName, "version", version, "previous_version", previousVersion)
+		if dryRun {
+			app.logger.DebugContext(ctx, "dry run: update agent runtime endpoint skipped")
+			return previousVersion, nil
 		}
 		resp, err := app.ctrlClient.UpdateAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.UpdateAgentRuntimeEndpointInput{
 			AgentRuntimeId:      aws.String(id),
@@ -191,9 +464,9 @@ func (app *App) createOrUpdateAgentRuntimeEndpoint(ctx context.Context, id strin
 			Description:         coalesce(current.Description, aws.String(fmt.Sprintf("Managed by %s", AppName))),
 		})
 		if err != nil {
-			return fmt.Errorf("UpdateAgentRuntimeEndpoint: %w", err)
+			return "", fmt.Errorf("UpdateAgentRuntimeEndpoint: %w", err)
 		}
-		slog.DebugContext(ctx, "updated agent runtime endpoint", "name", endpointName, "arn", aws.ToString(resp.AgentRuntimeEndpointArn))
+		app.logger.DebugContext(ctx, "updated agent runtime endpoint", "name", endpointName, "arn", aws.ToString(resp.AgentRuntimeEndpointArn))
 	}
-	return nil
+	return previousVersion, nil
 }
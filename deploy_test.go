@@ -3,6 +3,11 @@ package acrun
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
@@ -21,6 +26,7 @@ func TestDeploy_Create(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	endpointName := "test-endpoint"
 
@@ -80,6 +86,7 @@ func TestDeploy_Create(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -87,13 +94,14 @@ func TestDeploy_Create(t *testing.T) {
 	app.SetOutput(&stdout, &stderr)
 
 	opt := &DeployOption{
+		NoWaitEndpoint:  true,
 		DryRun:          false,
 		EndpointName:    &endpointName,
 		WaitDuration:    1 * time.Minute,
 		PollingInterval: 15 * time.Nanosecond,
 	}
 
-	err = app.Deploy(context.Background(), opt)
+	_, err = app.Deploy(context.Background(), opt)
 	require.NoError(t, err)
 }
 
@@ -105,6 +113,7 @@ func TestDeploy_Update(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	endpointName := "test-endpoint"
 
@@ -190,6 +199,7 @@ func TestDeploy_Update(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -197,13 +207,14 @@ func TestDeploy_Update(t *testing.T) {
 	app.SetOutput(&stdout, &stderr)
 
 	opt := &DeployOption{
+		NoWaitEndpoint:  true,
 		DryRun:          false,
 		EndpointName:    &endpointName,
 		WaitDuration:    1 * time.Minute,
 		PollingInterval: 15 * time.Nanosecond,
 	}
 
-	err = app.Deploy(context.Background(), opt)
+	_, err = app.Deploy(context.Background(), opt)
 	require.NoError(t, err)
 }
 
@@ -215,6 +226,7 @@ func TestDeploy_DryRun(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	endpointName := "test-endpoint"
 
@@ -241,6 +253,7 @@ func TestDeploy_DryRun(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -248,11 +261,12 @@ func TestDeploy_DryRun(t *testing.T) {
 	app.SetOutput(&stdout, &stderr)
 
 	opt := &DeployOption{
-		DryRun:       true,
-		EndpointName: &endpointName,
+		NoWaitEndpoint: true,
+		DryRun:         true,
+		EndpointName:   &endpointName,
 	}
 
-	err = app.Deploy(context.Background(), opt)
+	_, err = app.Deploy(context.Background(), opt)
 	require.NoError(t, err)
 }
 
@@ -264,6 +278,7 @@ func TestDeploy_DefaultEndpointRejected(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	app, err := NewWithClient(
 		context.Background(),
@@ -273,16 +288,356 @@ func TestDeploy_DefaultEndpointRejected(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
 	defaultEndpoint := "DEFAULT"
 	opt := &DeployOption{
-		DryRun:       false,
-		EndpointName: &defaultEndpoint,
+		NoWaitEndpoint: true,
+		DryRun:         false,
+		EndpointName:   &defaultEndpoint,
 	}
 
-	err = app.Deploy(context.Background(), opt)
+	_, err = app.Deploy(context.Background(), opt)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "DEFAULT endpoint is not allowed")
 }
+
+func TestDeploy_UpdateFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	mockClient := NewMockBedrockAgentCoreClient(ctrl)
+	mockECRClient := NewMockECRClient(ctrl)
+	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
+
+	endpointName := "test-endpoint"
+
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+			AgentRuntimes: []types.AgentRuntime{
+				{
+					AgentRuntimeId:   aws.String("existing-runtime-id"),
+					AgentRuntimeName: aws.String("hosted_agent_dummy"),
+					AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/existing-runtime-id"),
+				},
+			},
+		}, nil)
+
+	mockCtrlClient.EXPECT().
+		GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+			TargetVersion: aws.String("1"),
+		}, nil)
+
+	gomock.InOrder(
+		mockCtrlClient.EXPECT().
+			GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+				AgentRuntimeId:      aws.String("existing-runtime-id"),
+				AgentRuntimeArn:     aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/existing-runtime-id"),
+				AgentRuntimeVersion: aws.String("1"),
+			}, nil),
+		mockCtrlClient.EXPECT().
+			UpdateAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.UpdateAgentRuntimeOutput{
+				AgentRuntimeId:      aws.String("existing-runtime-id"),
+				AgentRuntimeVersion: aws.String("2"),
+			}, nil),
+		mockCtrlClient.EXPECT().
+			GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+				AgentRuntimeId:      aws.String("existing-runtime-id"),
+				AgentRuntimeVersion: aws.String("2"),
+				Status:              types.AgentRuntimeStatusUpdateFailed,
+				FailureReason:       aws.String("image not found"),
+			}, nil),
+	)
+	// No endpoint update must happen after the failure
+
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+		aws.Config{},
+		mockCtrlClient,
+		mockClient,
+		mockECRClient,
+		mockSTSClient,
+		mockS3Client,
+	)
+	require.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+
+	opt := &DeployOption{
+		NoWaitEndpoint:  true,
+		EndpointName:    &endpointName,
+		WaitDuration:    1 * time.Minute,
+		PollingInterval: 15 * time.Nanosecond,
+	}
+
+	_, err = app.Deploy(context.Background(), opt)
+	require.Error(t, err)
+	var tse *TerminalStatusError
+	require.ErrorAs(t, err, &tse)
+	require.Equal(t, "UPDATE_FAILED", tse.Status)
+	require.Equal(t, "image not found", tse.Reason)
+	require.Contains(t, err.Error(), "image not found")
+}
+
+func TestDeploy_WaitEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	mockClient := NewMockBedrockAgentCoreClient(ctrl)
+	mockECRClient := NewMockECRClient(ctrl)
+	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
+
+	endpointName := "test-endpoint"
+
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+			AgentRuntimes: []types.AgentRuntime{
+				{
+					AgentRuntimeId:   aws.String("existing-runtime-id"),
+					AgentRuntimeName: aws.String("hosted_agent_dummy"),
+					AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/existing-runtime-id"),
+				},
+			},
+		}, nil)
+
+	gomock.InOrder(
+		// resolve the current version of the endpoint
+		mockCtrlClient.EXPECT().
+			GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+				TargetVersion: aws.String("1"),
+			}, nil),
+		// endpoint exists (will update)
+		mockCtrlClient.EXPECT().
+			GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+				TargetVersion: aws.String("1"),
+				Description:   aws.String("Existing endpoint"),
+			}, nil),
+		// wait for the endpoint to serve the new version
+		mockCtrlClient.EXPECT().
+			GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+				Status:        types.AgentRuntimeEndpointStatusUpdating,
+				LiveVersion:   aws.String("1"),
+				TargetVersion: aws.String("2"),
+			}, nil),
+		mockCtrlClient.EXPECT().
+			GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+				Status:        types.AgentRuntimeEndpointStatusReady,
+				LiveVersion:   aws.String("2"),
+				TargetVersion: aws.String("2"),
+			}, nil),
+	)
+
+	gomock.InOrder(
+		mockCtrlClient.EXPECT().
+			GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+				AgentRuntimeId:      aws.String("existing-runtime-id"),
+				AgentRuntimeArn:     aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/existing-runtime-id"),
+				AgentRuntimeVersion: aws.String("1"),
+			}, nil),
+		mockCtrlClient.EXPECT().
+			UpdateAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.UpdateAgentRuntimeOutput{
+				AgentRuntimeId:      aws.String("existing-runtime-id"),
+				AgentRuntimeVersion: aws.String("2"),
+			}, nil),
+		mockCtrlClient.EXPECT().
+			GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+				AgentRuntimeId:      aws.String("existing-runtime-id"),
+				AgentRuntimeVersion: aws.String("2"),
+				Status:              types.AgentRuntimeStatusReady,
+			}, nil),
+	)
+
+	mockCtrlClient.EXPECT().
+		UpdateAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.UpdateAgentRuntimeEndpointOutput{}, nil)
+
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+		aws.Config{},
+		mockCtrlClient,
+		mockClient,
+		mockECRClient,
+		mockSTSClient,
+		mockS3Client,
+	)
+	require.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+
+	opt := &DeployOption{
+		EndpointName:         &endpointName,
+		WaitDuration:         1 * time.Minute,
+		PollingInterval:      15 * time.Nanosecond,
+		EndpointWaitDuration: 1 * time.Minute,
+	}
+
+	_, err = app.Deploy(context.Background(), opt)
+	require.NoError(t, err)
+}
+
+func TestDeploy_NoDiff(t *testing.T) {
+	cases := []struct {
+		Name            string
+		ForceNewVersion bool
+	}{
+		{
+			Name: "skip new version",
+		},
+		{
+			Name:            "force new version",
+			ForceNewVersion: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+			mockClient := NewMockBedrockAgentCoreClient(ctrl)
+			mockECRClient := NewMockECRClient(ctrl)
+			mockSTSClient := NewMockSTSClient(ctrl)
+			mockS3Client := NewMockS3Client(ctrl)
+
+			endpointName := "test-endpoint"
+			arn := "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/existing-runtime-id"
+
+			local, err := (&App{agentRuntimeFilepath: "testdata/agent_runtime.json", logger: slog.Default()}).loadAgentRuntimeFile(context.Background())
+			require.NoError(t, err)
+			remote := func(version string) *bedrockagentcorecontrol.GetAgentRuntimeOutput {
+				return &bedrockagentcorecontrol.GetAgentRuntimeOutput{
+					AgentRuntimeId:          aws.String("existing-runtime-id"),
+					AgentRuntimeArn:         aws.String(arn),
+					AgentRuntimeName:        local.AgentRuntimeName,
+					AgentRuntimeVersion:     aws.String(version),
+					RoleArn:                 local.RoleArn,
+					AgentRuntimeArtifact:    local.AgentRuntimeArtifact,
+					NetworkConfiguration:    local.NetworkConfiguration,
+					ProtocolConfiguration:   local.ProtocolConfiguration,
+					EnvironmentVariables:    local.EnvironmentVariables,
+					AuthorizerConfiguration: local.AuthorizerConfiguration,
+					Status:                  types.AgentRuntimeStatusReady,
+				}
+			}
+
+			mockCtrlClient.EXPECT().
+				ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+					AgentRuntimes: []types.AgentRuntime{
+						{
+							AgentRuntimeId:   aws.String("existing-runtime-id"),
+							AgentRuntimeName: aws.String("hosted_agent_dummy"),
+							AgentRuntimeArn:  aws.String(arn),
+						},
+					},
+				}, nil)
+			mockCtrlClient.EXPECT().
+				GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+					LiveVersion:   aws.String("1"),
+					TargetVersion: aws.String("1"),
+					Status:        types.AgentRuntimeEndpointStatusReady,
+				}, nil).
+				AnyTimes()
+			if tc.ForceNewVersion {
+				gomock.InOrder(
+					mockCtrlClient.EXPECT().
+						GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(remote("1"), nil),
+					mockCtrlClient.EXPECT().
+						UpdateAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(&bedrockagentcorecontrol.UpdateAgentRuntimeOutput{
+							AgentRuntimeId:      aws.String("existing-runtime-id"),
+							AgentRuntimeVersion: aws.String("2"),
+						}, nil),
+					mockCtrlClient.EXPECT().
+						GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(remote("2"), nil),
+				)
+				mockCtrlClient.EXPECT().
+					UpdateAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&bedrockagentcorecontrol.UpdateAgentRuntimeEndpointOutput{}, nil)
+			} else {
+				// GetAgentRuntime for diff and for waiting; no UpdateAgentRuntime nor UpdateAgentRuntimeEndpoint
+				mockCtrlClient.EXPECT().
+					GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(remote("1"), nil).
+					Times(2)
+			}
+
+			app, err := NewWithClient(
+				context.Background(),
+				&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+				aws.Config{},
+				mockCtrlClient,
+				mockClient,
+				mockECRClient,
+				mockSTSClient,
+				mockS3Client,
+			)
+			require.NoError(t, err)
+
+			var stdout, stderr bytes.Buffer
+			app.SetOutput(&stdout, &stderr)
+
+			outputsFile := filepath.Join(t.TempDir(), "github_output")
+			opt := &DeployOption{
+				NoWaitEndpoint:  true,
+				EndpointName:    &endpointName,
+				WaitDuration:    1 * time.Minute,
+				PollingInterval: 15 * time.Nanosecond,
+				ForceNewVersion: tc.ForceNewVersion,
+				Output:          "json",
+				OutputsFile:     outputsFile,
+			}
+			result, err := app.Deploy(context.Background(), opt)
+			require.NoError(t, err)
+
+			expectedVersion := "1"
+			if tc.ForceNewVersion {
+				expectedVersion = "2"
+			}
+			require.Equal(t, "existing-runtime-id", result.AgentRuntimeID)
+			require.Equal(t, arn, result.AgentRuntimeArn)
+			require.Equal(t, endpointName, result.EndpointName)
+			require.Equal(t, expectedVersion, result.Version)
+			require.Equal(t, "1", result.PreviousVersion)
+			require.Equal(t, tc.ForceNewVersion, result.NewVersion)
+			require.False(t, result.Created)
+			require.NotEmpty(t, result.Phases)
+
+			var printed DeployResult
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &printed))
+			require.Equal(t, result.Version, printed.Version)
+
+			bs, err := os.ReadFile(outputsFile)
+			require.NoError(t, err)
+			require.Contains(t, string(bs), "version="+expectedVersion+"\n")
+			require.Contains(t, string(bs), "agent_runtime_arn="+arn+"\n")
+			require.Contains(t, string(bs), "new_version="+strconv.FormatBool(tc.ForceNewVersion)+"\n")
+		})
+	}
+}
@@ -2,21 +2,27 @@ package acrun
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"log/slog"
+	"path/filepath"
+	"strconv"
 	"strings"
 
-	"github.com/aereal/jsondiff"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
 	"github.com/fatih/color"
-	"github.com/itchyny/gojq"
 )
 
 type DiffOption struct {
 	Qualifier *string `help:"the qualifier to compare; allow endpoint name or version number"`
 	Ignore    string  `help:"ignore diff by jq query" default:""`
 	ExitCode  bool    `help:"exit with code 2 if there are differences" default:"false"`
+	Out       string  `name:"out" help:"write a plan file to apply with deploy --plan. the qualifier must be an endpoint name" type:"path"`
+	Prune     bool    `name:"prune" help:"show endpoints that exist remotely but are not declared as deleted" default:"false"`
+	Output    string  `name:"output" short:"o" help:"output format (text, json)" default:"text" enum:"text,json"`
+	From      string  `name:"from" help:"the source to compare from; an endpoint name, a version number or a file path. default is the qualifier"`
+	To        string  `name:"to" help:"the source to compare to; an endpoint name, a version number or a file path. default is the agent runtime file"`
+	Format    string  `name:"format" help:"diff format (unified, json-patch, markdown, json)" default:"unified" enum:"unified,json-patch,markdown,json"`
 }
 
 func coloredDiff(src string) string {
@@ -33,18 +39,40 @@ func coloredDiff(src string) string {
 	return b.String()
 }
 
-func (app *App) Diff(ctx context.Context, opt *DiffOption) error {
-	local, err := app.loadAgentRuntimeFile(ctx)
+func (app *App) Diff(ctx context.Context, opt *DiffOption) (*DiffResult, error) {
+	if opt.From != "" || opt.To != "" {
+		return app.diffFromTo(ctx, opt)
+	}
+	local, ext, err := app.loadAgentRuntimeFileWithExtension(ctx)
 	if err != nil {
-		return fmt.Errorf("load agent runtime file: %w", err)
+		return nil, fmt.Errorf("load agent runtime file: %w", err)
+	}
+	if ext.CodeSource != nil {
+		// resolve the content-hash key without uploading
+		if err := app.packageCode(ctx, local, ext.CodeSource, false); err != nil {
+			return nil, fmt.Errorf("package code: %w", err)
+		}
 	}
 	var remote *AgentRuntime
-	resp, err := app.GetAgentRuntime(ctx, local.AgentRuntimeName, opt.Qualifier)
+	var resp *bedrockagentcorecontrol.GetAgentRuntimeOutput
+	if opt.Out != "" {
+		endpointName := fillEndpointName(opt.Qualifier)
+		if _, err := strconv.ParseUint(endpointName, 10, 64); err == nil {
+			return nil, fmt.Errorf("--out requires an endpoint name as the qualifier, got version %s", endpointName)
+		}
+		if endpointName == DefaultEndpointName {
+			return nil, errors.New("--out is not allowed for the DEFAULT endpoint")
+		}
+		// compare against the same remote that deploy updates from
+		resp, err = app.getDeployBase(ctx, local.AgentRuntimeName, aws.String(endpointName))
+	} else {
+		resp, err = app.GetAgentRuntime(ctx, local.AgentRuntimeName, opt.Qualifier)
+	}
 	if err != nil {
 		if !errors.Is(err, ErrAgentRuntimeNotFound) {
-			return fmt.Errorf("get remote agent runtime: %w", err)
+			return nil, fmt.Errorf("get remote agent runtime: %w", err)
 		}
-		slog.InfoContext(
+		app.logger.InfoContext(
 			ctx,
 			fmt.Sprintf("remote AgentRuntime not found, %s deploy will create a new agent runtime",
 				AppName,
@@ -54,34 +82,17 @@ func (app *App) Diff(ctx context.Context, opt *DiffOption) error {
 	} else {
 		remote, err = newAgentRuntimeFromResponse(resp)
 		if err != nil {
-			return fmt.Errorf("newAgentRuntimeFromResponse: %w", err)
+			return nil, fmt.Errorf("newAgentRuntimeFromResponse: %w", err)
 		}
-	}
-
-	opts := []jsondiff.Option{}
-	if ignore := opt.Ignore; ignore != "" {
-		if p, err := gojq.Parse(ignore); err != nil {
-			return fmt.Errorf("failed to parse ignore query: %s %w", ignore, err)
-		} else {
-			opts = append(opts, jsondiff.Ignore(p))
+		if local.Tags != nil {
+			// GetAgentRuntime does not include tags
+			remote.Tags, err = app.listTags(ctx, aws.ToString(resp.AgentRuntimeArn))
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	var remoteAny, localAny interface{}
-	remoteJSON, err := marshalAgentRuntime(remote, "  ")
-	if err != nil {
-		return fmt.Errorf("marshalAgentRuntime: %w", err)
-	}
-	if err := json.Unmarshal(remoteJSON, &remoteAny); err != nil {
-		return fmt.Errorf("unmarshal remote agent runtime: %w", err)
-	}
-	localJSON, err := marshalAgentRuntime(local, "  ")
-	if err != nil {
-		return fmt.Errorf("marshalAgentRuntime: %w", err)
-	}
-	if err := json.Unmarshal(localJSON, &localAny); err != nil {
-		return fmt.Errorf("unmarshal local agent runtime: %w", err)
-	}
 	remoteARN := "(known after deploy)"
 	remoteVersion := ""
 	if remote != nil && resp != nil && resp.AgentRuntimeArn != nil {
@@ -93,21 +104,254 @@ func (app *App) Diff(ctx context.Context, opt *DiffOption) error {
 			remoteVersion = *opt.Qualifier
 		}
 	}
-	hasDiff := false
-	if diff, err := jsondiff.Diff(
-		&jsondiff.Input{Name: remoteARN + ";" + remoteVersion, X: remoteAny},
-		&jsondiff.Input{Name: app.agentRuntimeFilepath, X: localAny},
-		opts...,
-	); err != nil {
-		return fmt.Errorf("failed to diff: %w", err)
-	} else if diff != "" {
-		hasDiff = true
-		fmt.Print(coloredDiff(diff))
-	} else {
-		slog.InfoContext(ctx, "no differences found", "agent_runtime_name", *local.AgentRuntimeName, "remote_arn", remoteARN, "local_file", app.agentRuntimeFilepath)
+	defaults, err := app.serverDefaults(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d, err := newAgentRuntimeDiff(remote, local, remoteARN+";"+remoteVersion, app.agentRuntimeFilepath, opt.Ignore, defaults)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := d.unified(app.masker)
+	if err != nil {
+		return nil, err
+	}
+	result := &DiffResult{
+		AgentRuntimeName: *local.AgentRuntimeName,
+		Qualifier:        aws.ToString(opt.Qualifier),
+		HasDiff:          diff != "",
+		Diff:             diff,
+		Changes:          d.changes(app.masker),
+		PlanFile:         opt.Out,
+	}
+	if remote != nil {
+		result.RemoteArn = aws.ToString(resp.AgentRuntimeArn)
+		result.RemoteVersion = aws.ToString(resp.AgentRuntimeVersion)
+	}
+	if opt.Out != "" {
+		if err := app.writeDiffPlan(ctx, opt, local, ext, resp, remote != nil, diff); err != nil {
+			return nil, err
+		}
+	}
+	if opt.Output != "json" {
+		if err := app.writeDiff(opt.Format, d, diff); err != nil {
+			return nil, err
+		}
+	}
+	if diff == "" {
+		app.logger.InfoContext(ctx, "no differences found", "agent_runtime_name", *local.AgentRuntimeName, "remote_arn", remoteARN, "local_file", app.agentRuntimeFilepath)
+	}
+	if ext.Endpoints != nil {
+		deployedVersion := "(known after deploy)"
+		if remote != nil && diff == "" {
+			// deploy does not create a new version when there are no differences
+			deployedVersion = aws.ToString(resp.AgentRuntimeVersion)
+		}
+		actions, err := app.diffEndpoints(ctx, local, ext.Endpoints, remote != nil, deployedVersion, opt)
+		if err != nil {
+			return nil, err
+		}
+		if opt.Output != "json" {
+			switch opt.Format {
+			case "unified":
+				writeEndpointActions(app.stdout, actions)
+			case "markdown":
+				writeMarkdownEndpointActions(app.stdout, actions)
+			}
+		}
+		for _, a := range actions {
+			result.EndpointChanges = append(result.EndpointChanges, a.String())
+			if a.Type != endpointActionUndeclared {
+				result.HasDiff = true
+			}
+		}
+	}
+	return app.finishDiff(opt, result)
+}
+
+func (app *App) finishDiff(opt *DiffOption, result *DiffResult) (*DiffResult, error) {
+	if err := app.writeResult(opt.Output, result); err != nil {
+		return nil, err
 	}
-	if hasDiff && opt.ExitCode {
-		return ErrDiff
+	if result.HasDiff && opt.ExitCode {
+		return result, ErrDiff
+	}
+	return result, nil
+}
+
+// diffSource is a side of diff --from/--to.
+type diffSource struct {
+	label        string
+	agentRuntime *AgentRuntime
+	// remote is nil when the source is a file.
+	remote *bedrockagentcorecontrol.GetAgentRuntimeOutput
+}
+
+// isFileSource reports whether the --from/--to value is a file path.
+// Endpoint names and version numbers never contain '.' or '/'.
+func isFileSource(s string) bool {
+	return strings.ContainsAny(s, "./"+string(filepath.Separator))
+}
+
+// diffFromTo compares any two sources, each an endpoint name, a version number or a file.
+func (app *App) diffFromTo(ctx context.Context, opt *DiffOption) (*DiffResult, error) {
+	if opt.Out != "" {
+		return nil, errors.New("--out cannot be used with --from/--to")
+	}
+	from := opt.From
+	if from == "" {
+		from = fillEndpointName(opt.Qualifier)
+	}
+	to := opt.To
+	if to == "" {
+		to = app.agentRuntimeFilepath
+	}
+
+	var name *string
+	load := func(s string) (*diffSource, error) {
+		if isFileSource(s) {
+			return app.loadFileDiffSource(ctx, s)
+		}
+		if name == nil {
+			// remote sources belong to the agent runtime of the agent runtime file
+			local, err := app.loadAgentRuntimeFile(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("load agent runtime file: %w", err)
+			}
+			name = local.AgentRuntimeName
+		}
+		resp, err := app.GetAgentRuntime(ctx, name, aws.String(s))
+		if err != nil {
+			return nil, fmt.Errorf("get agent runtime %s: %w", s, err)
+		}
+		agentRuntime, err := newAgentRuntimeFromResponse(resp)
+		if err != nil {
+			return nil, fmt.Errorf("newAgentRuntimeFromResponse: %w", err)
+		}
+		return &diffSource{
+			label:        aws.ToString(resp.AgentRuntimeArn) + ";Version " + aws.ToString(resp.AgentRuntimeVersion),
+			agentRuntime: agentRuntime,
+			remote:       resp,
+		}, nil
+	}
+	fromSrc, err := load(from)
+	if err != nil {
+		return nil, err
+	}
+	toSrc, err := load(to)
+	if err != nil {
+		return nil, err
+	}
+	// GetAgentRuntime does not include tags. Tags belong to the agent runtime, not to a version,
+	// so they are compared only when a file declares them.
+	for _, src := range []*diffSource{fromSrc, toSrc} {
+		other := toSrc
+		if src == toSrc {
+			other = fromSrc
+		}
+		if src.remote == nil || other.remote != nil || other.agentRuntime.Tags == nil {
+			continue
+		}
+		if src.agentRuntime.Tags, err = app.listTags(ctx, aws.ToString(src.remote.AgentRuntimeArn)); err != nil {
+			return nil, err
+		}
+	}
+
+	defaults, err := app.serverDefaults(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d, err := newAgentRuntimeDiff(fromSrc.agentRuntime, toSrc.agentRuntime, fromSrc.label, toSrc.label, opt.Ignore, defaults)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := d.unified(app.masker)
+	if err != nil {
+		return nil, err
+	}
+	result := &DiffResult{
+		AgentRuntimeName: aws.ToString(toSrc.agentRuntime.AgentRuntimeName),
+		From:             from,
+		To:               to,
+		HasDiff:          diff != "",
+		Diff:             diff,
+		Changes:          d.changes(app.masker),
+	}
+	if opt.Output != "json" {
+		if err := app.writeDiff(opt.Format, d, diff); err != nil {
+			return nil, err
+		}
+	}
+	if diff == "" {
+		app.logger.InfoContext(ctx, "no differences found", "from", fromSrc.label, "to", toSrc.label)
+	}
+	return app.finishDiff(opt, result)
+}
+
+// loadFileDiffSource loads an agent runtime file as a diff source.
+func (app *App) loadFileDiffSource(ctx context.Context, path string) (*diffSource, error) {
+	agentRuntime, ext, err := app.loadAgentRuntimeFileAt(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("load agent runtime file %s: %w", path, err)
+	}
+	if ext.CodeSource != nil {
+		// the source directory is relative to the file, not to the agent runtime file
+		src := *ext.CodeSource
+		if !filepath.IsAbs(src.Dir) {
+			if src.Dir, err = filepath.Abs(filepath.Join(filepath.Dir(path), src.Dir)); err != nil {
+				return nil, fmt.Errorf("resolve code source dir: %w", err)
+			}
+		}
+		if err := app.packageCode(ctx, agentRuntime, &src, false); err != nil {
+			return nil, fmt.Errorf("package code: %w", err)
+		}
+	}
+	return &diffSource{label: path, agentRuntime: agentRuntime}, nil
+}
+
+// diffAgentRuntime returns the unified diff between the normalized JSON of the remote and local agent runtimes.
+// An empty string means there are no differences.
+func diffAgentRuntime(remote, local *AgentRuntime, remoteName, localName string, defaults []serverDefault) (string, error) {
+	d, err := newAgentRuntimeDiff(remote, local, remoteName, localName, "", defaults)
+	if err != nil {
+		return "", err
+	}
+	return d.unified(nil)
+}
+
+// diffEndpoints returns the changes that deploy makes to reconcile the declared endpoints.
+func (app *App) diffEndpoints(ctx context.Context, local *AgentRuntime, endpoints map[string]*EndpointConfig, exists bool, deployedVersion string, opt *DiffOption) ([]endpointAction, error) {
+	var id string
+	if exists {
+		var err error
+		id, err = app.GetAgentRuntimeIDByName(ctx, *local.AgentRuntimeName)
+		if err != nil {
+			return nil, fmt.Errorf("get agent runtime ID: %w", err)
+		}
+	}
+	actions, err := app.planEndpoints(ctx, id, endpoints, deployedVersion, fillEndpointName(opt.Qualifier), opt.Prune)
+	if err != nil {
+		return nil, fmt.Errorf("plan endpoints: %w", err)
+	}
+	return actions, nil
+}
+
+func (app *App) writeDiffPlan(ctx context.Context, opt *DiffOption, local *AgentRuntime, ext *AgentRuntimeExtension, resp *bedrockagentcorecontrol.GetAgentRuntimeOutput, exists bool, diff string) error {
+	var remoteARN, remoteVersion string
+	if exists {
+		remoteARN = aws.ToString(resp.AgentRuntimeArn)
+		remoteVersion = aws.ToString(resp.AgentRuntimeVersion)
+	}
+	var src *CodeSource
+	if ext.CodeSource != nil {
+		src = &CodeSource{
+			Dir:      app.resolveCodeSourceDir(ext.CodeSource),
+			Excludes: ext.CodeSource.Excludes,
+		}
+	}
+	plan, err := newPlan(fillEndpointName(opt.Qualifier), remoteARN, remoteVersion, local, src, ext.Endpoints, diff)
+	if err != nil {
+		return err
 	}
-	return nil
+	return app.writePlan(ctx, opt.Out, plan)
 }
@@ -0,0 +1,245 @@
+package acrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/aereal/jsondiff"
+	"github.com/itchyny/gojq"
+)
+
+// DiffChange is a changed path between two agent runtimes.
+type DiffChange struct {
+	// Path is the JSON Pointer (RFC 6901) of the changed value.
+	Path string `json:"path"`
+	// Type is one of "added", "removed" and "changed".
+	Type string `json:"type"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+const (
+	diffChangeAdded   = "added"
+	diffChangeRemoved = "removed"
+	diffChangeChanged = "changed"
+)
+
+// JSONPatchOperation is an operation of JSON Patch (RFC 6902).
+type JSONPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// MarshalJSON omits value only for the remove operation, as add, replace and test require it even if it is null.
+func (o JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	type operation JSONPatchOperation
+	return json.Marshal(operation(o))
+}
+
+// agentRuntimeDiff is the difference between the normalized JSON documents of two agent runtimes.
+type agentRuntimeDiff struct {
+	fromName, toName string
+	from, to         any
+}
+
+// newAgentRuntimeDiff normalizes both agent runtimes, removes the paths matched by the ignore jq query
+// and suppresses the server defaults.
+func newAgentRuntimeDiff(from, to *AgentRuntime, fromName, toName, ignore string, defaults []serverDefault) (*agentRuntimeDiff, error) {
+	d := &agentRuntimeDiff{fromName: fromName, toName: toName}
+	var err error
+	if d.from, err = agentRuntimeDocument(from); err != nil {
+		return nil, err
+	}
+	if d.to, err = agentRuntimeDocument(to); err != nil {
+		return nil, err
+	}
+	if ignore != "" {
+		q, err := gojq.Parse("del(" + ignore + ")")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ignore query: %s %w", ignore, err)
+		}
+		if d.from, err = jsondiff.ModifyValue(q, d.from); err != nil {
+			return nil, fmt.Errorf("ignore %s: %w", fromName, err)
+		}
+		if d.to, err = jsondiff.ModifyValue(q, d.to); err != nil {
+			return nil, fmt.Errorf("ignore %s: %w", toName, err)
+		}
+	}
+	d.from, d.to = suppressServerDefaults(d.from, d.to, defaults)
+	return d, nil
+}
+
+// agentRuntimeDocument returns the agent runtime as a generic JSON value.
+func agentRuntimeDocument(v *AgentRuntime) (any, error) {
+	bs, err := marshalAgentRuntime(v, "")
+	if err != nil {
+		return nil, fmt.Errorf("marshalAgentRuntime: %w", err)
+	}
+	var doc any
+	if err := json.Unmarshal(bs, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal agent runtime: %w", err)
+	}
+	return doc, nil
+}
+
+// unified returns the unified diff. An empty string means there are no differences.
+// Secret environment variables are masked by the masker; nil masks nothing.
+func (d *agentRuntimeDiff) unified(masker *secretMasker) (string, error) {
+	from, to := masker.maskDocument(d.from), masker.maskDocument(d.to)
+	diff, err := jsondiff.Diff(
+		&jsondiff.Input{Name: d.fromName, X: from},
+		&jsondiff.Input{Name: d.toName, X: to},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to diff: %w", err)
+	}
+	return diff, nil
+}
+
+// changes returns the changed paths. Objects are compared key by key; arrays are compared as a whole.
+func (d *agentRuntimeDiff) changes(masker *secretMasker) []DiffChange {
+	from, to := masker.maskDocument(d.from), masker.maskDocument(d.to)
+	// a missing agent runtime is compared as an empty object, so that each field is listed as added
+	if from == nil {
+		from = map[string]any{}
+	}
+	if to == nil {
+		to = map[string]any{}
+	}
+	changes := []DiffChange{}
+	walkChanges("", from, to, &changes)
+	return changes
+}
+
+func walkChanges(path string, from, to any, changes *[]DiffChange) {
+	fromMap, fromOK := from.(map[string]any)
+	toMap, toOK := to.(map[string]any)
+	if !fromOK || !toOK {
+		if !reflect.DeepEqual(from, to) {
+			*changes = append(*changes, DiffChange{Path: path, Type: diffChangeChanged, Old: from, New: to})
+		}
+		return
+	}
+	keys := slices.Sorted(maps.Keys(fromMap))
+	for k := range toMap {
+		if _, ok := fromMap[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		p := path + "/" + escapeJSONPointer(k)
+		fv, inFrom := fromMap[k]
+		tv, inTo := toMap[k]
+		switch {
+		case !inTo:
+			*changes = append(*changes, DiffChange{Path: p, Type: diffChangeRemoved, Old: fv})
+		case !inFrom:
+			*changes = append(*changes, DiffChange{Path: p, Type: diffChangeAdded, New: tv})
+		default:
+			walkChanges(p, fv, tv, changes)
+		}
+	}
+}
+
+func escapeJSONPointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// jsonPatch converts the changes to JSON Patch operations.
+func jsonPatch(changes []DiffChange) []JSONPatchOperation {
+	ops := make([]JSONPatchOperation, 0, len(changes))
+	for _, c := range changes {
+		switch c.Type {
+		case diffChangeAdded:
+			ops = append(ops, JSONPatchOperation{Op: "add", Path: c.Path, Value: c.New})
+		case diffChangeRemoved:
+			ops = append(ops, JSONPatchOperation{Op: "remove", Path: c.Path})
+		default:
+			ops = append(ops, JSONPatchOperation{Op: "replace", Path: c.Path, Value: c.New})
+		}
+	}
+	return ops
+}
+
+// writeDiff writes the diff in the format. unified is the masked unified diff.
+func (app *App) writeDiff(format string, d *agentRuntimeDiff, unified string) error {
+	switch format {
+	case "json-patch":
+		return writeJSON(app.stdout, jsonPatch(d.changes(app.masker)))
+	case "json":
+		return writeJSON(app.stdout, struct {
+			From    string       `json:"from"`
+			To      string       `json:"to"`
+			Changes []DiffChange `json:"changes"`
+		}{d.fromName, d.toName, d.changes(app.masker)})
+	case "markdown":
+		return writeMarkdownDiff(app.stdout, d.fromName, d.toName, d.changes(app.masker))
+	default:
+		if unified != "" {
+			fmt.Fprint(app.stdout, coloredDiff(unified))
+		}
+		return nil
+	}
+}
+
+// writeMarkdownDiff writes the changes as a Markdown table, for posting as a pull request comment.
+func writeMarkdownDiff(w io.Writer, fromName, toName string, changes []DiffChange) error {
+	fmt.Fprintf(w, "**%s** → **%s**\n\n", markdownEscape(fromName), markdownEscape(toName))
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No differences.")
+		return nil
+	}
+	fmt.Fprintln(w, "| Path | Change | Old | New |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- |")
+	for _, c := range changes {
+		oldValue, err := markdownValue(c.Old, c.Type != diffChangeAdded)
+		if err != nil {
+			return err
+		}
+		newValue, err := markdownValue(c.New, c.Type != diffChangeRemoved)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "| `%s` | %s | %s | %s |\n", strings.ReplaceAll(c.Path, "|", `\|`), c.Type, oldValue, newValue)
+	}
+	return nil
+}
+
+func writeMarkdownEndpointActions(w io.Writer, actions []endpointAction) {
+	if len(actions) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	for _, a := range actions {
+		fmt.Fprintf(w, "- %s\n", markdownEscape(a.String()))
+	}
+}
+
+// markdownValue returns the value as compact JSON in a code span. present is false for the missing side of the change.
+func markdownValue(v any, present bool) (string, error) {
+	if !present {
+		return "", nil
+	}
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("marshal JSON: %w", err)
+	}
+	return "`" + strings.ReplaceAll(string(bs), "|", `\|`) + "`", nil
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`").Replace(s)
+}
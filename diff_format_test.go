@@ -0,0 +1,112 @@
+package acrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+)
+
+func newDiffFormatTestRuntimes() (*AgentRuntime, *AgentRuntime) {
+	from := &AgentRuntime{
+		AgentRuntimeName: aws.String("hosted_agent_dummy"),
+		RoleArn:          aws.String("arn:aws:iam::123456789012:role/Old"),
+		Description:      aws.String("to be removed"),
+		EnvironmentVariables: map[string]string{
+			"env":       "dev",
+			"API_TOKEN": "old-token",
+		},
+	}
+	to := &AgentRuntime{
+		AgentRuntimeName: aws.String("hosted_agent_dummy"),
+		RoleArn:          aws.String("arn:aws:iam::123456789012:role/New"),
+		EnvironmentVariables: map[string]string{
+			"env":       "dev",
+			"API_TOKEN": "new-token",
+			"a/b":       "added",
+		},
+	}
+	return from, to
+}
+
+func TestAgentRuntimeDiff_Changes(t *testing.T) {
+	from, to := newDiffFormatTestRuntimes()
+	d, err := newAgentRuntimeDiff(from, to, "from", "to", "", nil)
+	require.NoError(t, err)
+
+	require.Equal(t, []DiffChange{
+		{Path: "/description", Type: "removed", Old: "to be removed"},
+		{Path: "/environmentVariables/API_TOKEN", Type: "changed", Old: "old-token", New: "new-token"},
+		{Path: "/environmentVariables/a~1b", Type: "added", New: "added"},
+		{Path: "/roleArn", Type: "changed", Old: "arn:aws:iam::123456789012:role/Old", New: "arn:aws:iam::123456789012:role/New"},
+	}, d.changes(nil))
+
+	require.Equal(t, []JSONPatchOperation{
+		{Op: "remove", Path: "/description"},
+		{Op: "replace", Path: "/environmentVariables/API_TOKEN", Value: "new-token"},
+		{Op: "add", Path: "/environmentVariables/a~1b", Value: "added"},
+		{Op: "replace", Path: "/roleArn", Value: "arn:aws:iam::123456789012:role/New"},
+	}, jsonPatch(d.changes(nil)))
+
+	d, err = newAgentRuntimeDiff(from, to, "from", "to", ".environmentVariables, .description", nil)
+	require.NoError(t, err)
+	require.Len(t, d.changes(nil), 1)
+
+	d, err = newAgentRuntimeDiff(nil, to, "from", "to", "", nil)
+	require.NoError(t, err)
+	for _, c := range d.changes(nil) {
+		require.Equal(t, "added", c.Type, c.Path)
+	}
+}
+
+func TestAgentRuntimeDiff_Masked(t *testing.T) {
+	from, to := newDiffFormatTestRuntimes()
+	d, err := newAgentRuntimeDiff(from, to, "from", "to", "", nil)
+	require.NoError(t, err)
+
+	m := newSecretMasker()
+	unified, err := d.unified(m)
+	require.NoError(t, err)
+	require.NotContains(t, unified, "old-token")
+	require.NotContains(t, unified, "new-token")
+	require.Contains(t, unified, `-    "API_TOKEN": "`+m.maskValue("old-token")+`"`)
+	require.Contains(t, unified, `+    "API_TOKEN": "`+m.maskValue("new-token")+`"`)
+
+	var b bytes.Buffer
+	require.NoError(t, writeMarkdownDiff(&b, "from", "to", d.changes(m)))
+	require.NotContains(t, b.String(), "new-token")
+	require.Contains(t, b.String(), "| `/roleArn` | changed | `\"arn:aws:iam::123456789012:role/Old\"` | `\"arn:aws:iam::123456789012:role/New\"` |\n")
+	require.Contains(t, b.String(), "| `/description` | removed | `\"to be removed\"` |  |\n")
+
+	// machine formats are masked too
+	app := &App{stdout: &b, masker: m}
+	b.Reset()
+	require.NoError(t, app.writeDiff("json", d, unified))
+	var doc struct {
+		From    string       `json:"from"`
+		Changes []DiffChange `json:"changes"`
+	}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &doc))
+	require.Equal(t, "from", doc.From)
+	require.Equal(t, d.changes(m), doc.Changes)
+	require.NotContains(t, b.String(), "new-token")
+}
+
+func TestJSONPatchOperation_MarshalJSON(t *testing.T) {
+	bs, err := json.Marshal([]JSONPatchOperation{
+		{Op: "add", Path: "/a", Value: nil},
+		{Op: "replace", Path: "/b", Value: ""},
+		{Op: "replace", Path: "/c", Value: map[string]any{}},
+		{Op: "remove", Path: "/d"},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"op": "add", "path": "/a", "value": null},
+		{"op": "replace", "path": "/b", "value": ""},
+		{"op": "replace", "path": "/c", "value": {}},
+		{"op": "remove", "path": "/d"}
+	]`, string(bs))
+	require.NotContains(t, string(bs), `"path":"/d","value"`)
+}
@@ -21,6 +21,7 @@ func TestDiff_NoDifference(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Mock ListAgentRuntimes response (called by GetAgentRuntimeIDByName)
 	mockCtrlClient.EXPECT().
@@ -87,6 +88,7 @@ func TestDiff_NoDifference(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -94,7 +96,7 @@ func TestDiff_NoDifference(t *testing.T) {
 	app.SetOutput(&stdout, &stderr)
 
 	opt := &DiffOption{}
-	err = app.Diff(context.Background(), opt)
+	_, err = app.Diff(context.Background(), opt)
 	require.NoError(t, err)
 }
 
@@ -106,6 +108,7 @@ func TestDiff_WithDifference(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Mock ListAgentRuntimes response
 	mockCtrlClient.EXPECT().
@@ -172,6 +175,7 @@ func TestDiff_WithDifference(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -179,11 +183,10 @@ func TestDiff_WithDifference(t *testing.T) {
 	app.SetOutput(&stdout, &stderr)
 
 	opt := &DiffOption{}
-	err = app.Diff(context.Background(), opt)
+	_, err = app.Diff(context.Background(), opt)
 	require.NoError(t, err)
 
-	// Note: diff output goes to os.Stdout via fmt.Print, not app.stdout
-	// So we can't capture it in tests, but we can verify no error occurred
+	require.Contains(t, stdout.String(), "DummyServiceRole")
 }
 
 func TestDiff_RemoteNotFound(t *testing.T) {
@@ -194,6 +197,7 @@ func TestDiff_RemoteNotFound(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Mock ListAgentRuntimes response (empty - not found)
 	mockCtrlClient.EXPECT().
@@ -211,6 +215,7 @@ func TestDiff_RemoteNotFound(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -218,11 +223,10 @@ func TestDiff_RemoteNotFound(t *testing.T) {
 	app.SetOutput(&stdout, &stderr)
 
 	opt := &DiffOption{}
-	err = app.Diff(context.Background(), opt)
+	_, err = app.Diff(context.Background(), opt)
 	require.NoError(t, err)
 
-	// Note: diff output goes to os.Stdout via fmt.Print, not app.stdout
-	// So we can't capture it in tests, but we can verify no error occurred
+	require.Contains(t, stdout.String(), "hosted_agent_dummy")
 }
 
 func TestDiff_WithExitCode(t *testing.T) {
@@ -233,6 +237,7 @@ func TestDiff_WithExitCode(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Mock ListAgentRuntimes response
 	mockCtrlClient.EXPECT().
@@ -299,6 +304,7 @@ func TestDiff_WithExitCode(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -306,10 +312,109 @@ func TestDiff_WithExitCode(t *testing.T) {
 	app.SetOutput(&stdout, &stderr)
 
 	opt := &DiffOption{ExitCode: true}
-	err = app.Diff(context.Background(), opt)
+	_, err = app.Diff(context.Background(), opt)
 	require.Error(t, err)
 	require.True(t, errors.Is(err, ErrDiff))
 
-	// Note: diff output goes to os.Stdout via fmt.Print, not app.stdout
-	// So we can't capture it in tests, but we can verify the error occurred
+	require.Contains(t, stdout.String(), "DummyServiceRole")
+}
+
+func TestDiff_FromToVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+			AgentRuntimes: []types.AgentRuntime{
+				{
+					AgentRuntimeId:   aws.String("test-runtime-id"),
+					AgentRuntimeName: aws.String("hosted_agent_dummy"),
+					AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+				},
+			},
+		}, nil)
+	mockCtrlClient.EXPECT().
+		GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, in *bedrockagentcorecontrol.GetAgentRuntimeInput, _ ...func(*bedrockagentcorecontrol.Options)) (*bedrockagentcorecontrol.GetAgentRuntimeOutput, error) {
+			version := aws.ToString(in.AgentRuntimeVersion)
+			return &bedrockagentcorecontrol.GetAgentRuntimeOutput{
+				AgentRuntimeId:      aws.String("test-runtime-id"),
+				AgentRuntimeName:    aws.String("hosted_agent_dummy"),
+				AgentRuntimeArn:     aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+				AgentRuntimeVersion: aws.String(version),
+				RoleArn:             aws.String("arn:aws:iam::123456789012:role/Role" + version),
+				AgentRuntimeArtifact: &types.AgentRuntimeArtifactMemberContainerConfiguration{
+					Value: types.ContainerConfiguration{
+						ContainerUri: aws.String("123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample-mcp:dev"),
+					},
+				},
+				NetworkConfiguration: &types.NetworkConfiguration{
+					NetworkMode: types.NetworkModePublic,
+				},
+			}, nil
+		}).
+		Times(2)
+
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+		aws.Config{},
+		mockCtrlClient,
+		NewMockBedrockAgentCoreClient(ctrl),
+		NewMockECRClient(ctrl),
+		NewMockSTSClient(ctrl),
+		NewMockS3Client(ctrl),
+	)
+	require.NoError(t, err)
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+
+	result, err := app.Diff(context.Background(), &DiffOption{From: "7", To: "9", ExitCode: true})
+	require.True(t, errors.Is(err, ErrDiff))
+	require.True(t, result.HasDiff)
+	require.Equal(t, "7", result.From)
+	require.Equal(t, "9", result.To)
+	require.Contains(t, result.Diff, `-  "roleArn": "arn:aws:iam::123456789012:role/Role7"`)
+	require.Contains(t, result.Diff, `+  "roleArn": "arn:aws:iam::123456789012:role/Role9"`)
+	require.Contains(t, stdout.String(), "Role9")
+}
+
+func TestDiff_FromToFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+		aws.Config{},
+		NewMockBedrockAgentCoreControlClient(ctrl),
+		NewMockBedrockAgentCoreClient(ctrl),
+		NewMockECRClient(ctrl),
+		NewMockSTSClient(ctrl),
+		NewMockS3Client(ctrl),
+	)
+	require.NoError(t, err)
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+
+	result, err := app.Diff(context.Background(), &DiffOption{
+		From: "testdata/agent_runtime.json",
+		To:   "testdata/agent_runtime_with_tags.json",
+	})
+	require.NoError(t, err)
+	require.True(t, result.HasDiff)
+	require.Contains(t, result.Diff, `+  "tags": {`)
+
+	result, err = app.Diff(context.Background(), &DiffOption{
+		From:   "testdata/agent_runtime.json",
+		To:     "testdata/agent_runtime_with_tags.json",
+		Ignore: ".tags",
+	})
+	require.NoError(t, err)
+	require.False(t, result.HasDiff)
+
+	_, err = app.Diff(context.Background(), &DiffOption{From: "staging", Out: "plan.json"})
+	require.Error(t, err)
 }
@@ -2,11 +2,9 @@ package acrun
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log/slog"
-	"slices"
 	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
@@ -15,56 +13,52 @@ import (
 
 // ECRImagesOption represents the options for the ecr-images command.
 type ECRImagesOption struct {
-	Versions int `help:"Number of recent versions to include." default:"5"`
+	Versions int    `help:"Number of recent versions to include." default:"5"`
+	Output   string `name:"output" short:"o" help:"output format (text, json). text prints an image per line" default:"json" enum:"text,json"`
 }
 
 // ECRImages retrieves the list of ECR image URIs used by the AgentRuntime.
 // This includes images from all endpoints (DEFAULT and aliases) and recent N versions.
-func (app *App) ECRImages(ctx context.Context, opt *ECRImagesOption) error {
+// Images referenced by digest are listed as "repo@sha256:...", even if the reference also has a tag.
+func (app *App) ECRImages(ctx context.Context, opt *ECRImagesOption) (ECRImagesResult, error) {
 	agentRuntime, err := app.loadAgentRuntimeFile(ctx)
 	if err != nil {
-		return fmt.Errorf("load agent runtime file: %w", err)
+		return nil, fmt.Errorf("load agent runtime file: %w", err)
 	}
 
 	agentRuntimeName := *agentRuntime.AgentRuntimeName
-	slog.DebugContext(ctx, "starting ecr-images",
+	app.logger.DebugContext(ctx, "starting ecr-images",
 		"agent_runtime_name", agentRuntimeName,
 		"versions", opt.Versions,
 	)
 
 	id, err := app.GetAgentRuntimeIDByName(ctx, agentRuntimeName)
 	if err != nil {
-		return fmt.Errorf("get agent runtime ID: %w", err)
+		return nil, fmt.Errorf("get agent runtime ID: %w", err)
 	}
 
 	images := make(map[string]struct{})
 
 	if err := app.collectImagesFromEndpoints(ctx, id, images); err != nil {
-		return err
+		return nil, err
 	}
 
 	if opt.Versions > 0 {
 		if err := app.collectImagesFromVersions(ctx, id, opt.Versions, images); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	result := make([]string, 0, len(images))
+	result := make(ECRImagesResult, 0, len(images))
 	for img := range images {
 		result = append(result, img)
 	}
 	sort.Strings(result)
-
-	output, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshal JSON: %w", err)
-	}
-	fmt.Fprintln(app.stdout, string(output))
-	return nil
+	return result, app.writeResult(opt.Output, result)
 }
 
 func (app *App) collectImagesFromEndpoints(ctx context.Context, agentRuntimeID string, images map[string]struct{}) error {
-	slog.DebugContext(ctx, "collecting images from endpoints", "agent_runtime_id", agentRuntimeID)
+	app.logger.DebugContext(ctx, "collecting images from endpoints", "agent_runtime_id", agentRuntimeID)
 
 	endpointsOutput, err := app.ctrlClient.ListAgentRuntimeEndpoints(ctx, &bedrockagentcorecontrol.ListAgentRuntimeEndpointsInput{
 		AgentRuntimeId: aws.String(agentRuntimeID),
@@ -82,7 +76,7 @@ func (app *App) collectImagesFromEndpoints(ctx context.Context, agentRuntimeID s
 			EndpointName:   aws.String(endpointName),
 		})
 		if err != nil {
-			slog.WarnContext(ctx, "failed to get endpoint details",
+			app.logger.WarnContext(ctx, "failed to get endpoint details",
 				"endpoint", endpointName,
 				"error", err,
 			)
@@ -98,7 +92,7 @@ func (app *App) collectImagesFromEndpoints(ctx context.Context, agentRuntimeID s
 
 		if version != "" {
 			versions[version] = struct{}{}
-			slog.DebugContext(ctx, "found endpoint",
+			app.logger.DebugContext(ctx, "found endpoint",
 				"name", endpointName,
 				"version", version,
 			)
@@ -108,14 +102,14 @@ func (app *App) collectImagesFromEndpoints(ctx context.Context, agentRuntimeID s
 	for version := range versions {
 		uri, err := app.getContainerURIForVersion(ctx, agentRuntimeID, version)
 		if err != nil {
-			slog.WarnContext(ctx, "failed to get container URI for version",
+			app.logger.WarnContext(ctx, "failed to get container URI for version",
 				"version", version,
 				"error", err,
 			)
 			continue
 		}
 		if uri != "" {
-			images[uri] = struct{}{}
+			images[normalizeImageURI(uri)] = struct{}{}
 		}
 	}
 
@@ -123,49 +117,41 @@ func (app *App) collectImagesFromEndpoints(ctx context.Context, agentRuntimeID s
 }
 
 func (app *App) collectImagesFromVersions(ctx context.Context, agentRuntimeID string, versions int, images map[string]struct{}) error {
-	slog.DebugContext(ctx, "collecting images from versions",
+	app.logger.DebugContext(ctx, "collecting images from versions",
 		"agent_runtime_id", agentRuntimeID,
 		"versions", versions,
 	)
 
-	versionsOutput, err := app.ctrlClient.ListAgentRuntimeVersions(ctx, &bedrockagentcorecontrol.ListAgentRuntimeVersionsInput{
-		AgentRuntimeId: aws.String(agentRuntimeID),
-	})
+	summaries, err := app.listAgentRuntimeVersions(ctx, agentRuntimeID)
 	if err != nil {
-		return fmt.Errorf("ListAgentRuntimeVersions: %w", err)
+		return err
 	}
 
-	sortedVersions := make([]string, 0, len(versionsOutput.AgentRuntimes))
-	for _, v := range versionsOutput.AgentRuntimes {
+	// already sorted in descending order to get the newest versions first
+	sortedVersions := make([]string, 0, len(summaries))
+	for _, v := range summaries {
 		if v.AgentRuntimeVersion != nil {
 			sortedVersions = append(sortedVersions, *v.AgentRuntimeVersion)
 		}
 	}
-	slices.SortFunc(sortedVersions, func(a, b string) int {
-		// descending order to get the newest versions first
-		var va, vb int
-		fmt.Sscanf(a, "%d", &va) //nolint:errcheck
-		fmt.Sscanf(b, "%d", &vb) //nolint:errcheck
-		return vb - va
-	})
 
 	if len(sortedVersions) > versions {
 		sortedVersions = sortedVersions[:versions]
 	}
 
-	slog.DebugContext(ctx, "versions to collect", "versions", sortedVersions)
+	app.logger.DebugContext(ctx, "versions to collect", "versions", sortedVersions)
 
 	for _, version := range sortedVersions {
 		uri, err := app.getContainerURIForVersion(ctx, agentRuntimeID, version)
 		if err != nil {
-			slog.WarnContext(ctx, "failed to get container URI for version",
+			app.logger.WarnContext(ctx, "failed to get container URI for version",
 				"version", version,
 				"error", err,
 			)
 			continue
 		}
 		if uri != "" {
-			images[uri] = struct{}{}
+			images[normalizeImageURI(uri)] = struct{}{}
 		}
 	}
 
@@ -195,3 +181,26 @@ func extractContainerURI(artifact types.AgentRuntimeArtifact) string {
 		return ""
 	}
 }
+
+// parseImageURI splits the image URI into the repository, the tag and the digest.
+// It accepts "repo:tag", "repo@sha256:..." and "repo:tag@sha256:...". The registry may have a port.
+func parseImageURI(uri string) (repository, tag, digest string) {
+	repository = uri
+	if i := strings.Index(repository, "@"); i >= 0 {
+		repository, digest = repository[:i], repository[i+1:]
+	}
+	// a colon before the last slash is the port of the registry
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+	return repository, tag, digest
+}
+
+// normalizeImageURI returns the URI by digest if it has a digest, because the tag may have been moved to another image.
+func normalizeImageURI(uri string) string {
+	repository, _, digest := parseImageURI(uri)
+	if digest == "" {
+		return uri
+	}
+	return repository + "@" + digest
+}
@@ -21,6 +21,7 @@ func TestECRImages(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Setup expectations: ListAgentRuntimes to resolve name to ID
 	mockCtrlClient.EXPECT().
@@ -78,7 +79,7 @@ func TestECRImages(t *testing.T) {
 	mockCtrlClient.EXPECT().
 		ListAgentRuntimeVersions(gomock.Any(), &bedrockagentcorecontrol.ListAgentRuntimeVersionsInput{
 			AgentRuntimeId: aws.String("test-runtime-id"),
-		}).
+		}, gomock.Any()).
 		Return(&bedrockagentcorecontrol.ListAgentRuntimeVersionsOutput{
 			AgentRuntimes: []types.AgentRuntime{
 				{AgentRuntimeVersion: aws.String("1")},
@@ -169,12 +170,14 @@ func TestECRImages(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 	app.SetOutput(&stdout, &stderr)
 
-	err = app.ECRImages(context.Background(), &ECRImagesOption{
+	result, err := app.ECRImages(context.Background(), &ECRImagesOption{
 		Versions: 5,
+		Output:   "json",
 	})
 	require.NoError(t, err)
 
@@ -192,6 +195,7 @@ func TestECRImages(t *testing.T) {
 		"123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent:v5",
 	}
 	require.Equal(t, expected, images)
+	require.Equal(t, ECRImagesResult(expected), result)
 }
 
 func TestECRImages_KeepVersionsZero(t *testing.T) {
@@ -202,6 +206,7 @@ func TestECRImages_KeepVersionsZero(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Setup expectations: ListAgentRuntimes to resolve name to ID
 	mockCtrlClient.EXPECT().
@@ -259,13 +264,15 @@ func TestECRImages_KeepVersionsZero(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 	app.SetOutput(&stdout, &stderr)
 
 	// Versions=0 means only collect from endpoints, not from version history
-	err = app.ECRImages(context.Background(), &ECRImagesOption{
+	_, err = app.ECRImages(context.Background(), &ECRImagesOption{
 		Versions: 0,
+		Output:   "json",
 	})
 	require.NoError(t, err)
 
@@ -278,3 +285,122 @@ func TestECRImages_KeepVersionsZero(t *testing.T) {
 	}
 	require.Equal(t, expected, images)
 }
+
+func TestParseImageURI(t *testing.T) {
+	cases := []struct {
+		uri                     string
+		repository, tag, digest string
+		normalized              string
+	}{
+		{
+			uri:        "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent:v1",
+			repository: "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent",
+			tag:        "v1",
+			normalized: "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent:v1",
+		},
+		{
+			uri:        "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent@sha256:abc",
+			repository: "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent",
+			digest:     "sha256:abc",
+			normalized: "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent@sha256:abc",
+		},
+		{
+			uri:        "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent:v1@sha256:abc",
+			repository: "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent",
+			tag:        "v1",
+			digest:     "sha256:abc",
+			normalized: "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent@sha256:abc",
+		},
+		{
+			uri:        "localhost:5000/my-agent",
+			repository: "localhost:5000/my-agent",
+			normalized: "localhost:5000/my-agent",
+		},
+	}
+	for _, c := range cases {
+		repository, tag, digest := parseImageURI(c.uri)
+		require.Equal(t, c.repository, repository, c.uri)
+		require.Equal(t, c.tag, tag, c.uri)
+		require.Equal(t, c.digest, digest, c.uri)
+		require.Equal(t, c.normalized, normalizeImageURI(c.uri), c.uri)
+	}
+}
+
+func TestECRImages_Digest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+			AgentRuntimes: []types.AgentRuntime{
+				{
+					AgentRuntimeId:   aws.String("test-runtime-id"),
+					AgentRuntimeName: aws.String("hosted_agent_dummy"),
+					AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+				},
+			},
+		}, nil)
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimeEndpoints(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimeEndpointsOutput{
+			RuntimeEndpoints: []types.AgentRuntimeEndpoint{
+				{Name: aws.String("DEFAULT")},
+				{Name: aws.String("current")},
+			},
+		}, nil)
+	mockCtrlClient.EXPECT().
+		GetAgentRuntimeEndpoint(gomock.Any(), &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+			AgentRuntimeId: aws.String("test-runtime-id"),
+			EndpointName:   aws.String("DEFAULT"),
+		}).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{LiveVersion: aws.String("2")}, nil)
+	mockCtrlClient.EXPECT().
+		GetAgentRuntimeEndpoint(gomock.Any(), &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+			AgentRuntimeId: aws.String("test-runtime-id"),
+			EndpointName:   aws.String("current"),
+		}).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{LiveVersion: aws.String("1")}, nil)
+	// the same image, by tag and digest and by digest only
+	for version, uri := range map[string]string{
+		"1": "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent:v1@sha256:abc",
+		"2": "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent@sha256:abc",
+	} {
+		mockCtrlClient.EXPECT().
+			GetAgentRuntime(gomock.Any(), &bedrockagentcorecontrol.GetAgentRuntimeInput{
+				AgentRuntimeId:      aws.String("test-runtime-id"),
+				AgentRuntimeVersion: aws.String(version),
+			}).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+				AgentRuntimeArtifact: &types.AgentRuntimeArtifactMemberContainerConfiguration{
+					Value: types.ContainerConfiguration{ContainerUri: aws.String(uri)},
+				},
+			}, nil)
+	}
+
+	var stdout, stderr bytes.Buffer
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+		aws.Config{},
+		mockCtrlClient,
+		NewMockBedrockAgentCoreClient(ctrl),
+		NewMockECRClient(ctrl),
+		NewMockSTSClient(ctrl),
+		NewMockS3Client(ctrl),
+	)
+	require.NoError(t, err)
+	app.SetOutput(&stdout, &stderr)
+
+	result, err := app.ECRImages(context.Background(), &ECRImagesOption{Versions: 0, Output: "json"})
+	require.NoError(t, err)
+	var images []string
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &images))
+	require.Equal(t, []string{"123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent@sha256:abc"}, images)
+
+	// text prints an image per line
+	stdout.Reset()
+	require.NoError(t, app.writeResult("text", result))
+	require.Equal(t, "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent@sha256:abc\n", stdout.String())
+}
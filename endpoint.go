@@ -0,0 +1,317 @@
+package acrun
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+// EndpointOption represents the options for the endpoint command.
+type EndpointOption struct {
+	List     EndpointListOption     `cmd:"" help:"List endpoints of the agent runtime."`
+	Create   EndpointCreateOption   `cmd:"" help:"Create an endpoint pinned to a version."`
+	Delete   EndpointDeleteOption   `cmd:"" help:"Delete an endpoint."`
+	Describe EndpointDescribeOption `cmd:"" help:"Describe an endpoint."`
+}
+
+type EndpointListOption struct {
+	Output string `name:"output" short:"o" help:"output format (text, json)" default:"text" enum:"text,json"`
+}
+
+type EndpointCreateOption struct {
+	Name        string  `arg:"" help:"the endpoint name to create"`
+	Version     string  `name:"version" help:"the version the endpoint points to" required:""`
+	Description *string `name:"description" help:"the endpoint description. if not specified, 'Managed by acrun' is used"`
+	DryRun      bool    `name:"dry-run" help:"dry run" default:"false"`
+	Output      string  `name:"output" short:"o" help:"output format (text, json)" default:"text" enum:"text,json"`
+
+	NoWaitEndpoint       bool          `name:"no-wait-endpoint" help:"do not wait until the endpoint is ready and serves the version" default:"false"`
+	EndpointWaitDuration time.Duration `name:"endpoint-wait-duration" help:"maximum duration to wait until the endpoint serves the version" default:"10m"`
+	PollingInterval      time.Duration `name:"polling-interval" help:"polling interval to check the endpoint status" default:"5s"`
+}
+
+type EndpointDeleteOption struct {
+	Name            string        `arg:"" help:"the endpoint name to delete"`
+	DryRun          bool          `name:"dry-run" help:"dry run" default:"false"`
+	Force           bool          `name:"force" help:"force delete without confirmation" default:"false"`
+	Output          string        `name:"output" short:"o" help:"output format (text, json)" default:"text" enum:"text,json"`
+	WaitDuration    time.Duration `name:"wait-duration" help:"maximum duration to wait until the endpoint is deleted" default:"10m"`
+	PollingInterval time.Duration `name:"polling-interval" help:"polling interval to check the endpoint status" default:"5s"`
+}
+
+type EndpointDescribeOption struct {
+	Name   string `arg:"" help:"the endpoint name to describe"`
+	Output string `name:"output" short:"o" help:"output format (text, json)" default:"text" enum:"text,json"`
+}
+
+func (app *App) EndpointList(ctx context.Context, opt *EndpointListOption) (*EndpointListResult, error) {
+	agentRuntime, err := app.loadAgentRuntimeFile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load agent runtime file: %w", err)
+	}
+	id, err := app.GetAgentRuntimeIDByName(ctx, *agentRuntime.AgentRuntimeName)
+	if err != nil {
+		return nil, fmt.Errorf("get agent runtime ID: %w", err)
+	}
+	endpoints, err := app.listAgentRuntimeEndpoints(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if endpoints == nil {
+		endpoints = []EndpointStatus{}
+	}
+	result := &EndpointListResult{
+		AgentRuntimeName: *agentRuntime.AgentRuntimeName,
+		AgentRuntimeID:   id,
+		Endpoints:        endpoints,
+	}
+	return result, app.writeResult(opt.Output, result)
+}
+
+func (app *App) EndpointCreate(ctx context.Context, opt *EndpointCreateOption) (*EndpointCreateResult, error) {
+	if opt.Name == DefaultEndpointName {
+		return nil, errors.New("creating the DEFAULT endpoint is not allowed")
+	}
+	if _, err := strconv.ParseUint(opt.Version, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid version %q: must be a version number", opt.Version)
+	}
+	if opt.DryRun {
+		app.logger.WarnContext(ctx, "starting endpoint create in DRY RUN mode. No changes will be made.")
+		defer app.logger.WarnContext(ctx, "ended endpoint create in DRY RUN mode. No changes were made.")
+	}
+	id, err := app.loadAgentRuntimeID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := app.ctrlClient.GetAgentRuntime(ctx, &bedrockagentcorecontrol.GetAgentRuntimeInput{
+		AgentRuntimeId:      aws.String(id),
+		AgentRuntimeVersion: aws.String(opt.Version),
+	}); err != nil {
+		var nfe *types.ResourceNotFoundException
+		if errors.As(err, &nfe) {
+			return nil, fmt.Errorf("version %s not found", opt.Version)
+		}
+		return nil, fmt.Errorf("GetAgentRuntime: %w", err)
+	}
+	_, err = app.ctrlClient.GetAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+		AgentRuntimeId: aws.String(id),
+		EndpointName:   aws.String(opt.Name),
+	})
+	if err == nil {
+		return nil, fmt.Errorf("endpoint %s already exists; use deploy, promote or rollback to change its version", opt.Name)
+	}
+	var nfe *types.ResourceNotFoundException
+	if !errors.As(err, &nfe) {
+		return nil, fmt.Errorf("GetAgentRuntimeEndpoint: %w", err)
+	}
+
+	input := &bedrockagentcorecontrol.CreateAgentRuntimeEndpointInput{
+		AgentRuntimeId:      aws.String(id),
+		Name:                aws.String(opt.Name),
+		AgentRuntimeVersion: aws.String(opt.Version),
+		Description:         coalesce(opt.Description, aws.String(fmt.Sprintf("Managed by %s", AppName))),
+	}
+	result := &EndpointCreateResult{
+		AgentRuntimeID: id,
+		EndpointName:   opt.Name,
+		Version:        opt.Version,
+		DryRun:         opt.DryRun,
+	}
+	app.logger.InfoContext(ctx, "creating agent runtime endpoint", "name", opt.Name, "version", opt.Version)
+	app.DumpIfVerbose(ctx, "CreateAgentRuntimeEndpointInput", input)
+	if opt.DryRun {
+		app.logger.DebugContext(ctx, "dry run: create agent runtime endpoint skipped")
+		return result, app.writeResult(opt.Output, result)
+	}
+	resp, err := app.ctrlClient.CreateAgentRuntimeEndpoint(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("CreateAgentRuntimeEndpoint: %w", err)
+	}
+	app.logger.DebugContext(ctx, "created agent runtime endpoint", "name", opt.Name, "arn", aws.ToString(resp.AgentRuntimeEndpointArn))
+	result.EndpointArn = aws.ToString(resp.AgentRuntimeEndpointArn)
+	result.Status = string(resp.Status)
+	if !opt.NoWaitEndpoint {
+		if err := app.waitForAgentRuntimeEndpoint(ctx, id, opt.Name, opt.Version, opt.EndpointWaitDuration, opt.PollingInterval); err != nil {
+			return nil, fmt.Errorf("wait for agent runtime endpoint: %w", err)
+		}
+		result.Status = string(types.AgentRuntimeEndpointStatusReady)
+	}
+	app.logger.InfoContext(ctx, "created agent runtime endpoint", "name", opt.Name, "version", opt.Version)
+	return result, app.writeResult(opt.Output, result)
+}
+
+func (app *App) EndpointDelete(ctx context.Context, opt *EndpointDeleteOption) (*EndpointDeleteResult, error) {
+	if opt.Name == DefaultEndpointName {
+		return nil, errors.New("deleting the DEFAULT endpoint is not allowed")
+	}
+	if opt.DryRun {
+		app.logger.WarnContext(ctx, "starting endpoint delete in DRY RUN mode. No changes will be made.")
+		defer app.logger.WarnContext(ctx, "ended endpoint delete in DRY RUN mode. No changes were made.")
+	}
+	id, err := app.loadAgentRuntimeID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &EndpointDeleteResult{
+		AgentRuntimeID: id,
+		EndpointName:   opt.Name,
+		DryRun:         opt.DryRun,
+	}
+	current, err := app.ctrlClient.GetAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+		AgentRuntimeId: aws.String(id),
+		EndpointName:   aws.String(opt.Name),
+	})
+	if err != nil {
+		var nfe *types.ResourceNotFoundException
+		if errors.As(err, &nfe) {
+			app.logger.InfoContext(ctx, "agent runtime endpoint not found, nothing to delete", "name", opt.Name)
+			return result, app.writeResult(opt.Output, result)
+		}
+		return nil, fmt.Errorf("GetAgentRuntimeEndpoint: %w", err)
+	}
+	result.EndpointArn = aws.ToString(current.AgentRuntimeEndpointArn)
+	result.Version = aws.ToString(coalesce(current.TargetVersion, current.LiveVersion))
+	result.Status = string(current.Status)
+
+	if !opt.Force {
+		ok, err := app.confirm(ctx, fmt.Sprintf("Are you sure you want to delete endpoint '%s' (version: %s)?", opt.Name, result.Version))
+		if err != nil {
+			return nil, fmt.Errorf("delete endpoint: %w", err)
+		}
+		if !ok {
+			app.logger.InfoContext(ctx, "delete cancelled by user")
+			return result, app.writeResult(opt.Output, result)
+		}
+	}
+
+	input := &bedrockagentcorecontrol.DeleteAgentRuntimeEndpointInput{
+		AgentRuntimeId: aws.String(id),
+		EndpointName:   aws.String(opt.Name),
+	}
+	app.logger.InfoContext(ctx, "deleting agent runtime endpoint", "name", opt.Name, "id", id)
+	app.DumpIfVerbose(ctx, "DeleteAgentRuntimeEndpointInput", input)
+	if opt.DryRun {
+		app.logger.DebugContext(ctx, "dry run: delete agent runtime endpoint skipped")
+		return result, app.writeResult(opt.Output, result)
+	}
+	if _, err := app.ctrlClient.DeleteAgentRuntimeEndpoint(ctx, input); err != nil {
+		return nil, fmt.Errorf("DeleteAgentRuntimeEndpoint: %w", err)
+	}
+	if err := app.waitForAgentRuntimeEndpointDeleted(ctx, id, opt.Name, opt.WaitDuration, opt.PollingInterval); err != nil {
+		return nil, fmt.Errorf("wait for agent runtime endpoint deletion: %w", err)
+	}
+	app.logger.InfoContext(ctx, "deleted agent runtime endpoint", "name", opt.Name)
+	result.Deleted = true
+	return result, app.writeResult(opt.Output, result)
+}
+
+func (app *App) EndpointDescribe(ctx context.Context, opt *EndpointDescribeOption) (*EndpointStatus, error) {
+	id, err := app.loadAgentRuntimeID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := app.ctrlClient.GetAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+		AgentRuntimeId: aws.String(id),
+		EndpointName:   aws.String(opt.Name),
+	})
+	if err != nil {
+		var nfe *types.ResourceNotFoundException
+		if errors.As(err, &nfe) {
+			return nil, fmt.Errorf("endpoint %s not found", opt.Name)
+		}
+		return nil, fmt.Errorf("GetAgentRuntimeEndpoint: %w", err)
+	}
+	endpoint := newEndpointStatus(opt.Name, out)
+	endpoint.LiveContainerURI, endpoint.TargetContainerURI = app.endpointContainerURIs(ctx, id, endpoint)
+	return &endpoint, app.writeResult(opt.Output, &endpoint)
+}
+
+// loadAgentRuntimeID resolves the agent runtime ID from the name in the agent runtime file.
+func (app *App) loadAgentRuntimeID(ctx context.Context) (string, error) {
+	agentRuntime, err := app.loadAgentRuntimeFile(ctx)
+	if err != nil {
+		return "", fmt.Errorf("load agent runtime file: %w", err)
+	}
+	id, err := app.GetAgentRuntimeIDByName(ctx, *agentRuntime.AgentRuntimeName)
+	if err != nil {
+		return "", fmt.Errorf("get agent runtime ID: %w", err)
+	}
+	return id, nil
+}
+
+func (app *App) endpointContainerURIs(ctx context.Context, id string, e EndpointStatus) (string, string) {
+	uri := func(version string) string {
+		if version == "" {
+			return ""
+		}
+		u, err := app.getContainerURIForVersion(ctx, id, version)
+		if err != nil {
+			app.logger.WarnContext(ctx, "failed to get container URI for version", "version", version, "error", err)
+		}
+		return u
+	}
+	live := uri(e.LiveVersion)
+	if e.TargetVersion == e.LiveVersion {
+		return live, live
+	}
+	return live, uri(e.TargetVersion)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	bs, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal JSON: %w", err)
+	}
+	fmt.Fprintln(w, string(bs))
+	return nil
+}
+
+func writeEndpointsTable(w io.Writer, endpoints []EndpointStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENDPOINT\tSTATUS\tLIVE\tTARGET\tDESCRIPTION\tLAST UPDATED")
+	for _, e := range endpoints {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Name,
+			e.Status,
+			orDash(e.LiveVersion),
+			orDash(e.TargetVersion),
+			orDash(e.Description),
+			formatTime(e.LastUpdatedAt),
+		)
+	}
+	return tw.Flush()
+}
+
+func (e *EndpointStatus) writeText(w io.Writer) error {
+	return writeEndpointDetail(w, *e)
+}
+
+func writeEndpointDetail(w io.Writer, e EndpointStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	rows := [][2]string{
+		{"Name", e.Name},
+		{"ARN", e.Arn},
+		{"Description", e.Description},
+		{"Status", e.Status},
+		{"Failure Reason", e.FailureReason},
+		{"Live Version", e.LiveVersion},
+		{"Live Container URI", e.LiveContainerURI},
+		{"Target Version", e.TargetVersion},
+		{"Target Container URI", e.TargetContainerURI},
+		{"Created At", formatTime(e.CreatedAt)},
+		{"Last Updated At", formatTime(e.LastUpdatedAt)},
+	}
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s:\t%s\n", row[0], orDash(row[1]))
+	}
+	return tw.Flush()
+}
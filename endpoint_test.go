@@ -0,0 +1,302 @@
+package acrun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newEndpointTestApp(t *testing.T, ctrl *gomock.Controller) (*App, *MockBedrockAgentCoreControlClient, *bytes.Buffer) {
+	t.Helper()
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+			AgentRuntimes: []types.AgentRuntime{
+				{
+					AgentRuntimeId:   aws.String("test-runtime-id"),
+					AgentRuntimeName: aws.String("hosted_agent_dummy"),
+					AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+				},
+			},
+		}, nil).
+		AnyTimes()
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+		aws.Config{},
+		mockCtrlClient,
+		NewMockBedrockAgentCoreClient(ctrl),
+		NewMockECRClient(ctrl),
+		NewMockSTSClient(ctrl),
+		NewMockS3Client(ctrl),
+	)
+	require.NoError(t, err)
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+	return app, mockCtrlClient, &stdout
+}
+
+func TestEndpointList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app, mockCtrlClient, stdout := newEndpointTestApp(t, ctrl)
+
+	expectEndpoints(mockCtrlClient, map[string]types.AgentRuntimeEndpoint{
+		"DEFAULT": {LiveVersion: aws.String("3"), Status: types.AgentRuntimeEndpointStatusReady},
+		"canary":  {LiveVersion: aws.String("2"), TargetVersion: aws.String("3"), Status: types.AgentRuntimeEndpointStatusUpdating},
+	})
+
+	result, err := app.EndpointList(context.Background(), &EndpointListOption{Output: "json"})
+	require.NoError(t, err)
+
+	var out EndpointListResult
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+	require.Equal(t, *result, out)
+	require.Equal(t, EndpointListResult{
+		AgentRuntimeName: "hosted_agent_dummy",
+		AgentRuntimeID:   "test-runtime-id",
+		Endpoints: []EndpointStatus{
+			{Name: "DEFAULT", Status: "READY", LiveVersion: "3"},
+			{Name: "canary", Status: "UPDATING", LiveVersion: "2", TargetVersion: "3"},
+		},
+	}, out)
+}
+
+func TestEndpointList_FailedEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app, mockCtrlClient, _ := newEndpointTestApp(t, ctrl)
+
+	expectEndpoints(mockCtrlClient, map[string]types.AgentRuntimeEndpoint{
+		"DEFAULT": {LiveVersion: aws.String("3"), Status: types.AgentRuntimeEndpointStatusReady},
+		"canary":  {LiveVersion: aws.String("2"), TargetVersion: aws.String("3"), Status: types.AgentRuntimeEndpointStatusUpdateFailed},
+	})
+	// only the failed endpoint is read for its failure reason
+	mockCtrlClient.EXPECT().
+		GetAgentRuntimeEndpoint(gomock.Any(), &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+			AgentRuntimeId: aws.String("test-runtime-id"),
+			EndpointName:   aws.String("canary"),
+		}).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+			Name:          aws.String("canary"),
+			LiveVersion:   aws.String("2"),
+			TargetVersion: aws.String("3"),
+			Status:        types.AgentRuntimeEndpointStatusUpdateFailed,
+			FailureReason: aws.String("container failed to start"),
+		}, nil)
+
+	result, err := app.EndpointList(context.Background(), &EndpointListOption{Output: "json"})
+	require.NoError(t, err)
+	require.Equal(t, []EndpointStatus{
+		{Name: "DEFAULT", Status: "READY", LiveVersion: "3"},
+		{Name: "canary", Status: "UPDATE_FAILED", LiveVersion: "2", TargetVersion: "3", FailureReason: "container failed to start"},
+	}, result.Endpoints)
+}
+
+func TestEndpointCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app, mockCtrlClient, stdout := newEndpointTestApp(t, ctrl)
+
+	endpointInput := &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+		AgentRuntimeId: aws.String("test-runtime-id"),
+		EndpointName:   aws.String("canary"),
+	}
+	gomock.InOrder(
+		mockCtrlClient.EXPECT().
+			GetAgentRuntime(gomock.Any(), &bedrockagentcorecontrol.GetAgentRuntimeInput{
+				AgentRuntimeId:      aws.String("test-runtime-id"),
+				AgentRuntimeVersion: aws.String("3"),
+			}).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{AgentRuntimeVersion: aws.String("3")}, nil),
+		mockCtrlClient.EXPECT().
+			GetAgentRuntimeEndpoint(gomock.Any(), endpointInput).
+			Return(nil, &types.ResourceNotFoundException{}),
+		mockCtrlClient.EXPECT().
+			CreateAgentRuntimeEndpoint(gomock.Any(), &bedrockagentcorecontrol.CreateAgentRuntimeEndpointInput{
+				AgentRuntimeId:      aws.String("test-runtime-id"),
+				Name:                aws.String("canary"),
+				AgentRuntimeVersion: aws.String("3"),
+				Description:         aws.String("canary release"),
+			}).
+			Return(&bedrockagentcorecontrol.CreateAgentRuntimeEndpointOutput{
+				AgentRuntimeEndpointArn: aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id/runtime-endpoint/canary"),
+				Status:                  types.AgentRuntimeEndpointStatusCreating,
+			}, nil),
+		mockCtrlClient.EXPECT().
+			GetAgentRuntimeEndpoint(gomock.Any(), endpointInput).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+				Status:      types.AgentRuntimeEndpointStatusReady,
+				LiveVersion: aws.String("3"),
+			}, nil),
+	)
+
+	result, err := app.EndpointCreate(context.Background(), &EndpointCreateOption{
+		Name:                 "canary",
+		Version:              "3",
+		Description:          aws.String("canary release"),
+		Output:               "json",
+		EndpointWaitDuration: 1 * time.Minute,
+		PollingInterval:      15 * time.Nanosecond,
+	})
+	require.NoError(t, err)
+	expected := &EndpointCreateResult{
+		AgentRuntimeID: "test-runtime-id",
+		EndpointName:   "canary",
+		EndpointArn:    "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id/runtime-endpoint/canary",
+		Version:        "3",
+		Status:         "READY",
+	}
+	require.Equal(t, expected, result)
+	var out EndpointCreateResult
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+	require.Equal(t, *expected, out)
+}
+
+func TestEndpointCreate_AlreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app, mockCtrlClient, _ := newEndpointTestApp(t, ctrl)
+
+	mockCtrlClient.EXPECT().
+		GetAgentRuntime(gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{AgentRuntimeVersion: aws.String("3")}, nil)
+	mockCtrlClient.EXPECT().
+		GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{LiveVersion: aws.String("2")}, nil)
+
+	_, err := app.EndpointCreate(context.Background(), &EndpointCreateOption{Name: "canary", Version: "3"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already exists")
+}
+
+func TestEndpointDelete(t *testing.T) {
+	cases := []struct {
+		Name   string
+		DryRun bool
+	}{
+		{Name: "delete"},
+		{Name: "dry run", DryRun: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			app, mockCtrlClient, stdout := newEndpointTestApp(t, ctrl)
+
+			endpointInput := &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+				AgentRuntimeId: aws.String("test-runtime-id"),
+				EndpointName:   aws.String("canary"),
+			}
+			calls := []any{
+				mockCtrlClient.EXPECT().
+					GetAgentRuntimeEndpoint(gomock.Any(), endpointInput).
+					Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+						LiveVersion: aws.String("2"),
+						Status:      types.AgentRuntimeEndpointStatusReady,
+					}, nil),
+			}
+			if !tc.DryRun {
+				calls = append(calls,
+					mockCtrlClient.EXPECT().
+						DeleteAgentRuntimeEndpoint(gomock.Any(), &bedrockagentcorecontrol.DeleteAgentRuntimeEndpointInput{
+							AgentRuntimeId: aws.String("test-runtime-id"),
+							EndpointName:   aws.String("canary"),
+						}).
+						Return(&bedrockagentcorecontrol.DeleteAgentRuntimeEndpointOutput{}, nil),
+					mockCtrlClient.EXPECT().
+						GetAgentRuntimeEndpoint(gomock.Any(), endpointInput).
+						Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{Status: types.AgentRuntimeEndpointStatusDeleting}, nil),
+					mockCtrlClient.EXPECT().
+						GetAgentRuntimeEndpoint(gomock.Any(), endpointInput).
+						Return(nil, &types.ResourceNotFoundException{}),
+				)
+			}
+			gomock.InOrder(calls...)
+
+			result, err := app.EndpointDelete(context.Background(), &EndpointDeleteOption{
+				Name:            "canary",
+				DryRun:          tc.DryRun,
+				Force:           true,
+				Output:          "json",
+				WaitDuration:    1 * time.Minute,
+				PollingInterval: 15 * time.Nanosecond,
+			})
+			require.NoError(t, err)
+			expected := &EndpointDeleteResult{
+				AgentRuntimeID: "test-runtime-id",
+				EndpointName:   "canary",
+				Version:        "2",
+				Status:         "READY",
+				Deleted:        !tc.DryRun,
+				DryRun:         tc.DryRun,
+			}
+			require.Equal(t, expected, result)
+			var out EndpointDeleteResult
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+			require.Equal(t, *expected, out)
+		})
+	}
+}
+
+func TestEndpoint_DefaultRejected(t *testing.T) {
+	app := &App{agentRuntimeFilepath: "testdata/agent_runtime.json", logger: slog.Default()}
+	_, err := app.EndpointCreate(context.Background(), &EndpointCreateOption{Name: DefaultEndpointName, Version: "1"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "DEFAULT endpoint is not allowed")
+	_, err = app.EndpointDelete(context.Background(), &EndpointDeleteOption{Name: DefaultEndpointName, Force: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "DEFAULT endpoint is not allowed")
+}
+
+func TestEndpointDescribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app, mockCtrlClient, stdout := newEndpointTestApp(t, ctrl)
+
+	mockCtrlClient.EXPECT().
+		GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+			AgentRuntimeEndpointArn: aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id/runtime-endpoint/canary"),
+			Description:             aws.String("canary release"),
+			Status:                  types.AgentRuntimeEndpointStatusReady,
+			LiveVersion:             aws.String("3"),
+			TargetVersion:           aws.String("3"),
+		}, nil)
+	mockCtrlClient.EXPECT().
+		GetAgentRuntime(gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+			AgentRuntimeArtifact: &types.AgentRuntimeArtifactMemberContainerConfiguration{
+				Value: types.ContainerConfiguration{
+					ContainerUri: aws.String("123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample:v3"),
+				},
+			},
+		}, nil)
+
+	result, err := app.EndpointDescribe(context.Background(), &EndpointDescribeOption{Name: "canary", Output: "json"})
+	require.NoError(t, err)
+
+	var endpoint EndpointStatus
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &endpoint))
+	require.Equal(t, *result, endpoint)
+	require.Equal(t, EndpointStatus{
+		Name:               "canary",
+		Arn:                "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id/runtime-endpoint/canary",
+		Description:        "canary release",
+		Status:             "READY",
+		LiveVersion:        "3",
+		TargetVersion:      "3",
+		LiveContainerURI:   "123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample:v3",
+		TargetContainerURI: "123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample:v3",
+	}, endpoint)
+}
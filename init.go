@@ -3,7 +3,6 @@ package acrun
 import (
 	"context"
 	"fmt"
-	"log/slog"
 	"os"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -14,39 +13,48 @@ type InitOption struct {
 	Qualifier        *string `help:"the qualifier to initialize. if not specified, use the latest version." default:""`
 	Format           string  `help:"Output format. json or jsonnet" default:"jsonnet" enum:"json,jsonnet"`
 	ForceOverwrite   bool    `help:"Overwrite existing files without prompting" default:"false"`
+	Output           string  `name:"output" short:"o" help:"output format (text, json)" default:"text" enum:"text,json"`
 }
 
-func (app *App) Init(ctx context.Context, opt *InitOption) error {
-	slog.DebugContext(ctx, "starting init", "agent_runtime_name", opt.AgentRuntimeName, "qualifier", aws.ToString(opt.Qualifier), "format", opt.Format, "force_overwrite", opt.ForceOverwrite)
+func (app *App) Init(ctx context.Context, opt *InitOption) (*InitResult, error) {
+	app.logger.DebugContext(ctx, "starting init", "agent_runtime_name", opt.AgentRuntimeName, "qualifier", aws.ToString(opt.Qualifier), "format", opt.Format, "force_overwrite", opt.ForceOverwrite)
 	if aws.ToString(opt.Qualifier) == "" {
 		opt.Qualifier = aws.String(DefaultEndpointName)
 	}
 	resp, err := app.GetAgentRuntime(ctx, &opt.AgentRuntimeName, opt.Qualifier)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	slog.InfoContext(ctx, "fetched AgentRuntime", "name", opt.AgentRuntimeName, "arn", aws.ToString(resp.AgentRuntimeArn))
+	app.logger.InfoContext(ctx, "fetched AgentRuntime", "name", opt.AgentRuntimeName, "arn", aws.ToString(resp.AgentRuntimeArn))
 	def, err := newAgentRuntimeFromResponse(resp)
 	if err != nil {
-		return fmt.Errorf("newAgentRuntimeFromResponse: %w", err)
+		return nil, fmt.Errorf("newAgentRuntimeFromResponse: %w", err)
 	}
 	bs, err := marshalAgentRuntime(def, "  ")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	var filename string
 	if opt.Format == "jsonnet" {
 		bs, err = jsonToJsonnet(bs, "agent_runtime.jsonnet")
 		if err != nil {
-			return fmt.Errorf("jsonToJsonnet: %w", err)
+			return nil, fmt.Errorf("jsonToJsonnet: %w", err)
 		}
 		filename = DefaultAgentRuntimeFilenames[1]
 	} else {
 		filename = DefaultAgentRuntimeFilenames[0]
 	}
-	slog.InfoContext(ctx, "creating agent runtime file", "file", filename)
-	if err := app.saveFile(ctx, filename, bs, os.FileMode(0644), opt.ForceOverwrite); err != nil {
-		return fmt.Errorf("saveFile: %w", err)
+	app.logger.InfoContext(ctx, "creating agent runtime file", "file", filename)
+	written, err := app.saveFile(ctx, filename, bs, os.FileMode(0644), opt.ForceOverwrite)
+	if err != nil {
+		return nil, fmt.Errorf("saveFile: %w", err)
+	}
+	result := &InitResult{
+		AgentRuntimeName: opt.AgentRuntimeName,
+		AgentRuntimeArn:  aws.ToString(resp.AgentRuntimeArn),
+		Version:          aws.ToString(resp.AgentRuntimeVersion),
+		File:             filename,
+		Written:          written,
 	}
-	return nil
+	return result, app.writeResult(opt.Output, result)
 }
@@ -1,6 +1,7 @@
 package acrun
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"os"
@@ -22,6 +23,7 @@ func TestInit_JSON(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Mock ListAgentRuntimes response (called by GetAgentRuntimeIDByName)
 	mockCtrlClient.EXPECT().
@@ -76,6 +78,7 @@ func TestInit_JSON(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -85,8 +88,9 @@ func TestInit_JSON(t *testing.T) {
 		ForceOverwrite:   true,
 	}
 
-	err = app.Init(context.Background(), opt)
+	initResult, err := app.Init(context.Background(), opt)
 	require.NoError(t, err)
+	require.True(t, initResult.Written)
 
 	// Verify file was created
 	filename := filepath.Join(tempDir, "agent_runtime.json")
@@ -114,6 +118,7 @@ func TestInit_Jsonnet(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Mock ListAgentRuntimes response (called by GetAgentRuntimeIDByName)
 	mockCtrlClient.EXPECT().
@@ -168,6 +173,7 @@ func TestInit_Jsonnet(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -177,7 +183,7 @@ func TestInit_Jsonnet(t *testing.T) {
 		ForceOverwrite:   true,
 	}
 
-	err = app.Init(context.Background(), opt)
+	_, err = app.Init(context.Background(), opt)
 	require.NoError(t, err)
 
 	// Verify file was created
@@ -204,6 +210,7 @@ func TestInit_WithQualifier(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Mock ListAgentRuntimes response (called by GetAgentRuntimeIDByName)
 	mockCtrlClient.EXPECT().
@@ -251,6 +258,7 @@ func TestInit_WithQualifier(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -261,10 +269,85 @@ func TestInit_WithQualifier(t *testing.T) {
 		ForceOverwrite:   true,
 	}
 
-	err = app.Init(context.Background(), opt)
+	_, err = app.Init(context.Background(), opt)
 	require.NoError(t, err)
 
 	// Verify file was created
 	filename := filepath.Join(tempDir, "agent_runtime.json")
 	require.FileExists(t, filename)
 }
+
+func TestInit_OverwriteDeclined(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+			AgentRuntimes: []types.AgentRuntime{
+				{
+					AgentRuntimeId:   aws.String("test-runtime-id"),
+					AgentRuntimeName: aws.String("test-runtime"),
+					AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+				},
+			},
+		}, nil)
+	mockCtrlClient.EXPECT().
+		GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+			TargetVersion: aws.String("1"),
+		}, nil)
+	mockCtrlClient.EXPECT().
+		GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+			AgentRuntimeId:      aws.String("test-runtime-id"),
+			AgentRuntimeName:    aws.String("test-runtime"),
+			AgentRuntimeArn:     aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+			AgentRuntimeVersion: aws.String("1"),
+			RoleArn:             aws.String("arn:aws:iam::123456789012:role/test-role"),
+		}, nil)
+
+	tempDir := t.TempDir()
+	originalWd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tempDir))
+	defer os.Chdir(originalWd)
+	filename := filepath.Join(tempDir, "agent_runtime.json")
+	require.NoError(t, os.WriteFile(filename, []byte(`{"agentRuntimeName":"existing"}`), 0644))
+
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{},
+		aws.Config{},
+		mockCtrlClient,
+		NewMockBedrockAgentCoreClient(ctrl),
+		NewMockECRClient(ctrl),
+		NewMockSTSClient(ctrl),
+		NewMockS3Client(ctrl),
+		WithConfirmer(ConfirmerFunc(func(_ context.Context, _ string) (bool, error) {
+			return false, nil
+		})),
+	)
+	require.NoError(t, err)
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+
+	result, err := app.Init(context.Background(), &InitOption{
+		AgentRuntimeName: "test-runtime",
+		Format:           "json",
+		Output:           "json",
+	})
+	require.NoError(t, err)
+	require.False(t, result.Written)
+
+	var out InitResult
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+	require.False(t, out.Written)
+	require.Equal(t, "agent_runtime.json", out.File)
+
+	// the existing file is kept
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, `{"agentRuntimeName":"existing"}`, string(content))
+}
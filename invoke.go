@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log/slog"
 	"os"
 	"strings"
 
@@ -29,64 +28,55 @@ type InvokeOption struct {
 	TraceID            *string `name:"trace-id" help:"The trace identifier for request tracking."`
 	TraceParent        *string `name:"trace-parent" help:"The parent span identifier for distributed tracing."`
 	TraceState         *string `name:"trace-state" help:"The state information for distributed tracing."`
+
+	Output string `name:"output" short:"o" help:"output format (text, json). json prints the response metadata with the response body" default:"text" enum:"text,json"`
 }
 
-func (app *App) Invoke(ctx context.Context, opt *InvokeOption) error {
+func (app *App) Invoke(ctx context.Context, opt *InvokeOption) (*InvokeResult, error) {
 	agentRuntime, err := app.loadAgentRuntimeFile(ctx)
 	if err != nil {
-		return fmt.Errorf("load agent runtime file: %w", err)
+		return nil, fmt.Errorf("load agent runtime file: %w", err)
 	}
 	arn, err := app.GetAgentRuntimeARNByName(ctx, *agentRuntime.AgentRuntimeName)
 	if err != nil {
-		return fmt.Errorf("get agent runtime ARN by name: %w", err)
+		return nil, fmt.Errorf("get agent runtime ARN by name: %w", err)
 	}
-	slog.InfoContext(ctx, "invoking agent runtime", "name", *agentRuntime.AgentRuntimeName, "arn", arn)
+	app.logger.InfoContext(ctx, "invoking agent runtime", "name", *agentRuntime.AgentRuntimeName, "arn", arn)
 	var payloadReader io.Reader
 	if opt.Payload != nil {
 		payloadReader = strings.NewReader(*opt.Payload)
 	} else {
-		if isatty.IsTerminal(os.Stdin.Fd()) {
-			fmt.Println("Enter JSON payloads for the invoking function into STDIN. (Type Ctrl-D to close.)")
+		if f, ok := app.stdin.(*os.File); ok && isatty.IsTerminal(f.Fd()) {
+			fmt.Fprintln(app.stderr, "Enter JSON payloads for the invoking function into STDIN. (Type Ctrl-D to close.)")
 		}
-		payloadReader = os.Stdin
+		payloadReader = app.stdin
 	}
 	bs, err := io.ReadAll(payloadReader)
 	if err != nil {
-		return fmt.Errorf("read payload: %w", err)
-	}
-	if opt.ContentType == nil {
-		if json.Valid(bs) {
-			opt.ContentType = aws.String("application/json")
-		} else {
-			opt.ContentType = aws.String("text/plain")
-		}
+		return nil, fmt.Errorf("read payload: %w", err)
 	}
-	if opt.Accept == nil {
-		opt.Accept = aws.String("application/json")
-	}
-	resp, err := app.client.InvokeAgentRuntime(ctx, &bedrockagentcore.InvokeAgentRuntimeInput{
-		AgentRuntimeArn:    aws.String(arn),
-		Payload:            bs,
-		ContentType:        opt.ContentType,
-		Accept:             opt.Accept,
-		Qualifier:          aws.String(fillEndpointName(opt.EndpointName)),
-		McpProtocolVersion: opt.MCPProtocolVersion,
-		McpSessionId:       opt.MCPSessionID,
-		RuntimeSessionId:   opt.RuntimeSessionID,
-		RuntimeUserId:      opt.RuntimeUserID,
-		Baggage:            opt.Baggage,
-		TraceId:            opt.TraceID,
-		TraceParent:        opt.TraceParent,
-		TraceState:         opt.TraceState,
-	})
+	resp, err := app.invokeAgentRuntime(ctx, arn, bs, opt)
 	if err != nil {
-		return fmt.Errorf("InvokeAgentRuntime: %w", err)
+		return nil, err
 	}
+	defer resp.Response.Close()
 
-	stdout := bufio.NewWriter(app.stdout)
+	result := &InvokeResult{
+		AgentRuntimeArn:    arn,
+		EndpointName:       fillEndpointName(opt.EndpointName),
+		StatusCode:         aws.ToInt32(resp.StatusCode),
+		ContentType:        aws.ToString(resp.ContentType),
+		RuntimeSessionID:   aws.ToString(resp.RuntimeSessionId),
+		McpSessionID:       aws.ToString(resp.McpSessionId),
+		McpProtocolVersion: aws.ToString(resp.McpProtocolVersion),
+		TraceID:            aws.ToString(resp.TraceId),
+		TraceParent:        aws.ToString(resp.TraceParent),
+		TraceState:         aws.ToString(resp.TraceState),
+		Baggage:            aws.ToString(resp.Baggage),
+	}
 	args := []any{
-		"status_code", aws.ToInt32(resp.StatusCode),
-		"content_type", aws.ToString(resp.ContentType),
+		"status_code", result.StatusCode,
+		"content_type", result.ContentType,
 	}
 	if resp.TraceId != nil {
 		args = append(args, "trace_id", *resp.TraceId)
@@ -109,8 +99,53 @@ func (app *App) Invoke(ctx context.Context, opt *InvokeOption) error {
 	if resp.RuntimeSessionId != nil {
 		args = append(args, "runtime_session_id", *resp.RuntimeSessionId)
 	}
-	slog.InfoContext(ctx, "invoke agent runtime success", args...)
+	app.logger.InfoContext(ctx, "invoke agent runtime success", args...)
+	if opt.Output == "json" {
+		body, err := io.ReadAll(resp.Response)
+		if err != nil {
+			return nil, fmt.Errorf("read response: %w", err)
+		}
+		if json.Valid(body) {
+			result.Response = json.RawMessage(body)
+		} else {
+			result.Response = string(body)
+		}
+		return result, app.writeResult(opt.Output, result)
+	}
+	stdout := bufio.NewWriter(app.stdout)
 	_, err = io.Copy(stdout, resp.Response)
 	stdout.Flush()
-	return err
+	return result, err
+}
+
+func (app *App) invokeAgentRuntime(ctx context.Context, arn string, payload []byte, opt *InvokeOption) (*bedrockagentcore.InvokeAgentRuntimeOutput, error) {
+	if opt.ContentType == nil {
+		if json.Valid(payload) {
+			opt.ContentType = aws.String("application/json")
+		} else {
+			opt.ContentType = aws.String("text/plain")
+		}
+	}
+	if opt.Accept == nil {
+		opt.Accept = aws.String("application/json")
+	}
+	resp, err := app.client.InvokeAgentRuntime(ctx, &bedrockagentcore.InvokeAgentRuntimeInput{
+		AgentRuntimeArn:    aws.String(arn),
+		Payload:            payload,
+		ContentType:        opt.ContentType,
+		Accept:             opt.Accept,
+		Qualifier:          aws.String(fillEndpointName(opt.EndpointName)),
+		McpProtocolVersion: opt.MCPProtocolVersion,
+		McpSessionId:       opt.MCPSessionID,
+		RuntimeSessionId:   opt.RuntimeSessionID,
+		RuntimeUserId:      opt.RuntimeUserID,
+		Baggage:            opt.Baggage,
+		TraceId:            opt.TraceID,
+		TraceParent:        opt.TraceParent,
+		TraceState:         opt.TraceState,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("InvokeAgentRuntime: %w", err)
+	}
+	return resp, nil
 }
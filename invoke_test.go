@@ -22,6 +22,7 @@ func TestInvoke_WithPayload(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Mock ListAgentRuntimes response (called by GetAgentRuntimeARNByName)
 	mockCtrlClient.EXPECT().
@@ -63,6 +64,7 @@ func TestInvoke_WithPayload(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -74,7 +76,7 @@ func TestInvoke_WithPayload(t *testing.T) {
 		Payload: &payload,
 	}
 
-	err = app.Invoke(context.Background(), opt)
+	_, err = app.Invoke(context.Background(), opt)
 	require.NoError(t, err)
 
 	// Verify response body was written to stdout
@@ -90,6 +92,7 @@ func TestInvoke_WithHeaders(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	// Mock ListAgentRuntimes response
 	mockCtrlClient.EXPECT().
@@ -137,6 +140,7 @@ func TestInvoke_WithHeaders(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 
@@ -151,7 +155,7 @@ func TestInvoke_WithHeaders(t *testing.T) {
 		RuntimeSessionID: &runtimeSessionID,
 	}
 
-	err = app.Invoke(context.Background(), opt)
+	_, err = app.Invoke(context.Background(), opt)
 	require.NoError(t, err)
 
 	// Verify response body
@@ -186,6 +190,7 @@ func TestInvoke_AutoContentType(t *testing.T) {
 			mockClient := NewMockBedrockAgentCoreClient(ctrl)
 			mockECRClient := NewMockECRClient(ctrl)
 			mockSTSClient := NewMockSTSClient(ctrl)
+			mockS3Client := NewMockS3Client(ctrl)
 
 			// Mock ListAgentRuntimes response
 			mockCtrlClient.EXPECT().
@@ -224,6 +229,7 @@ func TestInvoke_AutoContentType(t *testing.T) {
 				mockClient,
 				mockECRClient,
 				mockSTSClient,
+				mockS3Client,
 			)
 			require.NoError(t, err)
 
@@ -235,7 +241,7 @@ func TestInvoke_AutoContentType(t *testing.T) {
 				// ContentType not specified - should be auto-detected
 			}
 
-			err = app.Invoke(context.Background(), opt)
+			_, err = app.Invoke(context.Background(), opt)
 			require.NoError(t, err)
 		})
 	}
@@ -2,15 +2,20 @@ package acrun
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"unicode"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/fujiwara/ssm-lookup/ssm"
 	"github.com/fujiwara/tfstate-lookup/tfstate"
@@ -20,8 +25,17 @@ import (
 )
 
 func MakeVM(ctx context.Context, stsClient STSClient, ecrClient ECRClient, awsCfg aws.Config, globalOpts *GlobalOption) *jsonnet.VM {
+	return makeVM(ctx, slog.Default(), stsClient, ecrClient, nil, nil, awsCfg, globalOpts)
+}
+
+// makeVM makes a Jsonnet VM with the native functions. The values read from Secrets Manager are added to masker.
+func makeVM(ctx context.Context, logger *slog.Logger, stsClient STSClient, ecrClient ECRClient, smClient SecretsManagerClient, masker *secretMasker, awsCfg aws.Config, globalOpts *GlobalOption) *jsonnet.VM {
 	vm := jsonnet.MakeVM()
-	for _, f := range defaultJsonnetNativeFuncs(ctx, stsClient, ecrClient, awsCfg) {
+	vm.Importer(newImporter(globalOpts.JPath))
+	for _, f := range defaultJsonnetNativeFuncs(ctx, logger, stsClient, ecrClient, awsCfg) {
+		vm.NativeFunction(f)
+	}
+	for _, f := range secretsManagerNativeFuncs(ctx, smClient, masker) {
 		vm.NativeFunction(f)
 	}
 
@@ -29,12 +43,12 @@ func MakeVM(ctx context.Context, stsClient STSClient, ecrClient ECRClient, awsCf
 	if globalOpts.TFState != "" {
 		state, err := tfstate.ReadURL(ctx, globalOpts.TFState)
 		if err != nil {
-			slog.WarnContext(ctx, "Failed to read tfstate, tfstate() function will not be available", "path", globalOpts.TFState, "error", err)
+			logger.WarnContext(ctx, "Failed to read tfstate, tfstate() function will not be available", "path", globalOpts.TFState, "error", err)
 		} else {
 			for _, f := range state.JsonnetNativeFuncs(ctx) {
 				vm.NativeFunction(f)
 			}
-			slog.DebugContext(ctx, "Loaded tfstate", "path", globalOpts.TFState)
+			logger.DebugContext(ctx, "Loaded tfstate", "path", globalOpts.TFState)
 		}
 	}
 
@@ -48,9 +62,44 @@ func MakeVM(ctx context.Context, stsClient STSClient, ecrClient ECRClient, awsCf
 		vm.ExtCode(k, v)
 	}
 
+	// top-level arguments, used when the file is a function
+	for k, v := range globalOpts.TLAStr {
+		vm.TLAVar(k, v)
+	}
+	for k, v := range globalOpts.TLACode {
+		vm.TLACode(k, v)
+	}
+	// files are read when the file is evaluated, so that a missing file is reported as an evaluation error
+	for k, path := range globalOpts.TLAStrFile {
+		vm.TLACode(k, fmt.Sprintf("importstr %q", absPath(path)))
+	}
+	for k, path := range globalOpts.TLACodeFile {
+		vm.TLACode(k, fmt.Sprintf("import %q", absPath(path)))
+	}
+
 	return vm
 }
 
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// tlaErrorHint returns a hint for the errors of top-level arguments.
+func tlaErrorHint(err error) string {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "Missing argument: "):
+		return "the file is a function; pass its parameters with --tla-str or --tla-code"
+	case strings.Contains(msg, "function has no parameter "):
+		return "a top-level argument given with --tla-str or --tla-code is not a parameter of the function"
+	default:
+		return ""
+	}
+}
+
 func jsonToJsonnet(src []byte, filepath string) ([]byte, error) {
 	s, err := formatter.Format(filepath, string(src), formatter.DefaultOptions())
 	if err != nil {
@@ -59,7 +108,7 @@ func jsonToJsonnet(src []byte, filepath string) ([]byte, error) {
 	return []byte(s), nil
 }
 
-func defaultJsonnetNativeFuncs(ctx context.Context, stsClient STSClient, ecrClient ECRClient, awsCfg aws.Config) []*jsonnet.NativeFunction {
+func defaultJsonnetNativeFuncs(ctx context.Context, logger *slog.Logger, stsClient STSClient, ecrClient ECRClient, awsCfg aws.Config) []*jsonnet.NativeFunction {
 	nativeFunctions := []*jsonnet.NativeFunction{
 		{
 			Name:   "env",
@@ -125,25 +174,86 @@ func defaultJsonnetNativeFuncs(ctx context.Context, stsClient STSClient, ecrClie
 				}
 
 				// Verify repository exists and get registry info
-				describeOutput, err := ecrClient.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{
-					RepositoryNames: []string{repositoryName},
-				})
+				repositoryURI, err := ecrRepositoryURI(ctx, ecrClient, repositoryName)
 				if err != nil {
-					return nil, fmt.Errorf("ecrImageUri: failed to describe repository: %w", err)
-				}
-				if len(describeOutput.Repositories) == 0 {
-					return nil, fmt.Errorf("ecrImageUri: repository not found: %s", repositoryName)
+					return nil, fmt.Errorf("ecrImageUri: %w", err)
 				}
 
-				repo := describeOutput.Repositories[0]
-
 				// Construct ECR URI with the tag
 				// repositoryURI is like "123456789012.dkr.ecr.us-west-2.amazonaws.com/repo-name"
-				uri := fmt.Sprintf("%s:%s", aws.ToString(repo.RepositoryUri), imageTag)
+				uri := fmt.Sprintf("%s:%s", repositoryURI, imageTag)
 
 				return uri, nil
 			},
 		},
+		{
+			Name:   "ecrImageDigestUri",
+			Params: []ast.Identifier{"repositoryName", "imageTag"},
+			Func: func(args []any) (any, error) {
+				if ecrClient == nil {
+					return nil, fmt.Errorf("ecrImageDigestUri: ECR client is not available")
+				}
+
+				repositoryName, ok := args[0].(string)
+				if !ok {
+					return nil, fmt.Errorf("ecrImageDigestUri: repositoryName must be a string")
+				}
+				imageTag, ok := args[1].(string)
+				if !ok {
+					return nil, fmt.Errorf("ecrImageDigestUri: imageTag must be a string")
+				}
+
+				repositoryURI, err := ecrRepositoryURI(ctx, ecrClient, repositoryName)
+				if err != nil {
+					return nil, fmt.Errorf("ecrImageDigestUri: %w", err)
+				}
+				digest, err := ecrImageDigest(ctx, ecrClient, repositoryName, imageTag)
+				if err != nil {
+					return nil, fmt.Errorf("ecrImageDigestUri: %w", err)
+				}
+
+				// the digest pins the image even if the tag is moved later
+				return fmt.Sprintf("%s@%s", repositoryURI, digest), nil
+			},
+		},
+		{
+			Name:   "ecrLatestImage",
+			Params: []ast.Identifier{"repositoryName", "tagRegex"},
+			Func: func(args []any) (any, error) {
+				if ecrClient == nil {
+					return nil, fmt.Errorf("ecrLatestImage: ECR client is not available")
+				}
+
+				repositoryName, ok := args[0].(string)
+				if !ok {
+					return nil, fmt.Errorf("ecrLatestImage: repositoryName must be a string")
+				}
+				tagRegex, ok := args[1].(string)
+				if !ok {
+					return nil, fmt.Errorf("ecrLatestImage: tagRegex must be a string")
+				}
+				re, err := regexp.Compile(tagRegex)
+				if err != nil {
+					return nil, fmt.Errorf("ecrLatestImage: invalid tagRegex: %w", err)
+				}
+
+				repositoryURI, err := ecrRepositoryURI(ctx, ecrClient, repositoryName)
+				if err != nil {
+					return nil, fmt.Errorf("ecrLatestImage: %w", err)
+				}
+				image, tag, err := ecrLatestImage(ctx, ecrClient, repositoryName, re)
+				if err != nil {
+					return nil, fmt.Errorf("ecrLatestImage: %w", err)
+				}
+				logger.DebugContext(ctx, "ecrLatestImage: selected image",
+					"repository", repositoryName,
+					"tag", tag,
+					"digest", aws.ToString(image.ImageDigest),
+					"pushed_at", aws.ToTime(image.ImagePushedAt),
+				)
+				return fmt.Sprintf("%s@%s", repositoryURI, aws.ToString(image.ImageDigest)), nil
+			},
+		},
 	}
 	cache := &sync.Map{}
 	ssmlookup := ssm.New(awsCfg, cache)
@@ -156,6 +266,71 @@ func defaultJsonnetNativeFuncs(ctx context.Context, stsClient STSClient, ecrClie
 	return nativeFunctions
 }
 
+// ecrRepositoryURI returns the URI of the repository, e.g. "123456789012.dkr.ecr.us-west-2.amazonaws.com/repo-name".
+func ecrRepositoryURI(ctx context.Context, ecrClient ECRClient, repositoryName string) (string, error) {
+	describeOutput, err := ecrClient.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{
+		RepositoryNames: []string{repositoryName},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe repository: %w", err)
+	}
+	if len(describeOutput.Repositories) == 0 {
+		return "", fmt.Errorf("repository not found: %s", repositoryName)
+	}
+	return aws.ToString(describeOutput.Repositories[0].RepositoryUri), nil
+}
+
+// ecrImageDigest returns the digest of the tagged image, e.g. "sha256:...".
+func ecrImageDigest(ctx context.Context, ecrClient ECRClient, repositoryName, imageTag string) (string, error) {
+	output, err := ecrClient.DescribeImages(ctx, &ecr.DescribeImagesInput{
+		RepositoryName: aws.String(repositoryName),
+		ImageIds:       []ecrtypes.ImageIdentifier{{ImageTag: aws.String(imageTag)}},
+	})
+	if err != nil {
+		var nfe *ecrtypes.ImageNotFoundException
+		if errors.As(err, &nfe) {
+			return "", fmt.Errorf("image tag %s is not found in repository %s", imageTag, repositoryName)
+		}
+		return "", fmt.Errorf("failed to describe images: %w", err)
+	}
+	if len(output.ImageDetails) == 0 || output.ImageDetails[0].ImageDigest == nil {
+		return "", fmt.Errorf("image tag %s is not found in repository %s", imageTag, repositoryName)
+	}
+	return aws.ToString(output.ImageDetails[0].ImageDigest), nil
+}
+
+// ecrLatestImage returns the most recently pushed image that has a tag matching re, and the matched tag.
+func ecrLatestImage(ctx context.Context, ecrClient ECRClient, repositoryName string, re *regexp.Regexp) (*ecrtypes.ImageDetail, string, error) {
+	var latest *ecrtypes.ImageDetail
+	var latestTag string
+	p := ecr.NewDescribeImagesPaginator(ecrClient, &ecr.DescribeImagesInput{
+		RepositoryName: aws.String(repositoryName),
+		Filter:         &ecrtypes.DescribeImagesFilter{TagStatus: ecrtypes.TagStatusTagged},
+	})
+	for p.HasMorePages() {
+		output, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to describe images: %w", err)
+		}
+		for _, image := range output.ImageDetails {
+			if image.ImageDigest == nil {
+				continue
+			}
+			i := slices.IndexFunc(image.ImageTags, re.MatchString)
+			if i < 0 {
+				continue
+			}
+			if latest == nil || aws.ToTime(image.ImagePushedAt).After(aws.ToTime(latest.ImagePushedAt)) {
+				latest, latestTag = &image, image.ImageTags[i]
+			}
+		}
+	}
+	if latest == nil {
+		return nil, "", fmt.Errorf("no image with a tag matching %s is found in repository %s", re, repositoryName)
+	}
+	return latest, latestTag, nil
+}
+
 // ToLowerCamelCase converts a snake_case string to lowerCamelCase.
 func ToLowerCamelCase(s string) string {
 	parts := strings.Split(s, "_")
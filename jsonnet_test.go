@@ -1,6 +1,18 @@
 package acrun
 
-import "testing"
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
 
 func TestToLowerCamelCase(t *testing.T) {
 	cases := []struct {
@@ -89,3 +101,117 @@ func TestToLowerCamelCase(t *testing.T) {
 		})
 	}
 }
+
+func TestTopLevelArguments(t *testing.T) {
+	newApp := func(opts *GlobalOption) *App {
+		return &App{
+			logger:               slog.Default(),
+			agentRuntimeFilepath: "testdata/agent_runtime_function.jsonnet",
+			vm:                   makeVM(context.Background(), slog.Default(), nil, nil, nil, nil, aws.Config{}, opts),
+		}
+	}
+
+	agentRuntime, err := newApp(&GlobalOption{
+		TLAStr:     map[string]string{"stage": "prod"},
+		TLACode:    map[string]string{"replicas": "3"},
+		TLAStrFile: map[string]string{"image": "testdata/tla/image.txt"},
+	}).loadAgentRuntimeFile(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "hosted_agent_prod", aws.ToString(agentRuntime.AgentRuntimeName))
+	require.Equal(t, map[string]string{"REPLICAS": "3"}, agentRuntime.EnvironmentVariables)
+	require.Equal(t, &types.AgentRuntimeArtifactMemberContainerConfiguration{
+		Value: types.ContainerConfiguration{
+			ContainerUri: aws.String("123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample-mcp:v1"),
+		},
+	}, agentRuntime.AgentRuntimeArtifact)
+
+	_, err = newApp(&GlobalOption{}).loadAgentRuntimeFile(context.Background())
+	require.ErrorContains(t, err, "pass its parameters with --tla-str or --tla-code")
+	require.ErrorContains(t, err, "Missing argument: stage")
+
+	_, err = newApp(&GlobalOption{
+		TLAStr: map[string]string{"stage": "prod", "stgae": "typo"},
+	}).loadAgentRuntimeFile(context.Background())
+	require.ErrorContains(t, err, "is not a parameter of the function")
+}
+
+func newECRNativeTestClient(ctrl *gomock.Controller) *MockECRClient {
+	client := NewMockECRClient(ctrl)
+	client.EXPECT().DescribeRepositories(gomock.Any(), &ecr.DescribeRepositoriesInput{
+		RepositoryNames: []string{"my-agent"},
+	}).Return(&ecr.DescribeRepositoriesOutput{
+		Repositories: []ecrtypes.Repository{
+			{RepositoryUri: aws.String("123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent")},
+		},
+	}, nil).AnyTimes()
+	return client
+}
+
+func TestECRImageDigestUri(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := newECRNativeTestClient(ctrl)
+	client.EXPECT().DescribeImages(gomock.Any(), &ecr.DescribeImagesInput{
+		RepositoryName: aws.String("my-agent"),
+		ImageIds:       []ecrtypes.ImageIdentifier{{ImageTag: aws.String("v1")}},
+	}).Return(&ecr.DescribeImagesOutput{
+		ImageDetails: []ecrtypes.ImageDetail{
+			{ImageDigest: aws.String("sha256:abc"), ImageTags: []string{"v1"}},
+		},
+	}, nil)
+	client.EXPECT().DescribeImages(gomock.Any(), &ecr.DescribeImagesInput{
+		RepositoryName: aws.String("my-agent"),
+		ImageIds:       []ecrtypes.ImageIdentifier{{ImageTag: aws.String("missing")}},
+	}).Return(nil, &ecrtypes.ImageNotFoundException{Message: aws.String("not found")})
+
+	vm := makeVM(context.Background(), slog.Default(), nil, client, nil, nil, aws.Config{}, &GlobalOption{})
+	out, err := vm.EvaluateAnonymousSnippet("test.jsonnet", `std.native('ecrImageDigestUri')('my-agent', 'v1')`)
+	require.NoError(t, err)
+	require.Equal(t, "\"123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent@sha256:abc\"\n", out)
+
+	_, err = vm.EvaluateAnonymousSnippet("test.jsonnet", `std.native('ecrImageDigestUri')('my-agent', 'missing')`)
+	require.ErrorContains(t, err, "image tag missing is not found in repository my-agent")
+}
+
+func TestECRLatestImage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pushedAt := func(hour int) *time.Time {
+		ts := time.Date(2026, 10, 1, hour, 0, 0, 0, time.UTC)
+		return &ts
+	}
+	client := newECRNativeTestClient(ctrl)
+	client.EXPECT().DescribeImages(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, input *ecr.DescribeImagesInput, _ ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error) {
+			require.Equal(t, "my-agent", aws.ToString(input.RepositoryName))
+			require.Equal(t, ecrtypes.TagStatusTagged, input.Filter.TagStatus)
+			if input.NextToken == nil {
+				return &ecr.DescribeImagesOutput{
+					ImageDetails: []ecrtypes.ImageDetail{
+						{ImageDigest: aws.String("sha256:old"), ImageTags: []string{"main-aaa"}, ImagePushedAt: pushedAt(1)},
+						{ImageDigest: aws.String("sha256:feature"), ImageTags: []string{"feature-ccc"}, ImagePushedAt: pushedAt(5)},
+					},
+					NextToken: aws.String("next"),
+				}, nil
+			}
+			return &ecr.DescribeImagesOutput{
+				ImageDetails: []ecrtypes.ImageDetail{
+					{ImageDigest: aws.String("sha256:new"), ImageTags: []string{"latest", "main-bbb"}, ImagePushedAt: pushedAt(3)},
+				},
+			}, nil
+		},
+	).AnyTimes()
+
+	vm := makeVM(context.Background(), slog.Default(), nil, client, nil, nil, aws.Config{}, &GlobalOption{})
+	out, err := vm.EvaluateAnonymousSnippet("test.jsonnet", `std.native('ecrLatestImage')('my-agent', '^main-')`)
+	require.NoError(t, err)
+	require.Equal(t, "\"123456789012.dkr.ecr.us-west-2.amazonaws.com/my-agent@sha256:new\"\n", out)
+
+	_, err = vm.EvaluateAnonymousSnippet("test.jsonnet", `std.native('ecrLatestImage')('my-agent', '^release-')`)
+	require.ErrorContains(t, err, "no image with a tag matching ^release- is found in repository my-agent")
+
+	_, err = vm.EvaluateAnonymousSnippet("test.jsonnet", `std.native('ecrLatestImage')('my-agent', '(')`)
+	require.ErrorContains(t, err, "invalid tagRegex")
+}
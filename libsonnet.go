@@ -0,0 +1,29 @@
+package acrun
+
+import (
+	_ "embed"
+
+	"github.com/google/go-jsonnet"
+)
+
+// LibsonnetImportPath is the import path of the bundled Jsonnet library.
+const LibsonnetImportPath = "acrun.libsonnet"
+
+//go:embed acrun.libsonnet
+var libsonnet string
+
+// importer imports the bundled acrun.libsonnet, and other files from the directory of the importing file or the library search paths.
+type importer struct {
+	files *jsonnet.FileImporter
+}
+
+func newImporter(jpath []string) *importer {
+	return &importer{files: &jsonnet.FileImporter{JPaths: jpath}}
+}
+
+func (i *importer) Import(importedFrom, importedPath string) (jsonnet.Contents, string, error) {
+	if importedPath == LibsonnetImportPath {
+		return jsonnet.MakeContents(libsonnet), "<acrun>/" + LibsonnetImportPath, nil
+	}
+	return i.files.Import(importedFrom, importedPath)
+}
@@ -0,0 +1,45 @@
+package acrun
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLibsonnet(t *testing.T) {
+	app := &App{
+		logger:               slog.Default(),
+		agentRuntimeFilepath: "testdata/agent_runtime_with_libsonnet.jsonnet",
+		vm:                   makeVM(context.Background(), slog.Default(), nil, nil, nil, nil, aws.Config{}, &GlobalOption{JPath: []string{"testdata/lib"}}),
+	}
+	agentRuntime, err := app.loadAgentRuntimeFile(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, "arn:aws:iam::123456789012:role/service-role/DummyServiceRole", aws.ToString(agentRuntime.RoleArn))
+	require.Equal(t, &types.NetworkConfiguration{
+		NetworkMode: types.NetworkModeVpc,
+		NetworkModeConfig: &types.VpcConfig{
+			Subnets:        []string{"subnet-1", "subnet-2"},
+			SecurityGroups: []string{"sg-1"},
+		},
+	}, agentRuntime.NetworkConfiguration)
+	require.Equal(t, types.ServerProtocolMcp, agentRuntime.ProtocolConfiguration.ServerProtocol)
+	require.Equal(t, &types.AuthorizerConfigurationMemberCustomJWTAuthorizer{
+		Value: types.CustomJWTAuthorizerConfiguration{
+			DiscoveryUrl:   aws.String("https://cognito-idp.us-west-2.amazonaws.com/us-west-2_abc/.well-known/openid-configuration"),
+			AllowedClients: []string{"client"},
+		},
+	}, agentRuntime.AuthorizerConfiguration)
+	require.Equal(t, &types.RequestHeaderConfigurationMemberRequestHeaderAllowlist{
+		Value: []string{"X-Request-ID"},
+	}, agentRuntime.RequestHeaderConfiguration)
+
+	// without --jpath, common.libsonnet is not found
+	app.vm = makeVM(context.Background(), slog.Default(), nil, nil, nil, nil, aws.Config{}, &GlobalOption{})
+	_, err = app.loadAgentRuntimeFile(context.Background())
+	require.ErrorContains(t, err, "common.libsonnet")
+}
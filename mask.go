@@ -0,0 +1,159 @@
+package acrun
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"strings"
+)
+
+// minSecretValueLength is the minimum length of the values read from Secrets Manager that identify their environment variables.
+// Shorter values such as "true" or "1" would mark unrelated environment variables with the same value.
+const minSecretValueLength = 8
+
+// DefaultSecretPatterns are the glob patterns of environment variable names whose values are masked.
+// Patterns are matched case-insensitively.
+var DefaultSecretPatterns = []string{"*SECRET*", "*TOKEN*", "*KEY*", "*PASSWORD*"}
+
+// secretMasker masks the values of secret-looking environment variables in human-readable output.
+// A nil secretMasker masks nothing.
+type secretMasker struct {
+	patterns []string
+	// values are the values read from Secrets Manager. They are used only to find the environment variables sourced from them.
+	values map[string]struct{}
+	// keys are the upper-cased names of the environment variables whose local values are read from Secrets Manager.
+	// They are masked on both sides of a diff, so that a rotated remote value is not shown.
+	keys map[string]struct{}
+	// salt is random per run, so that a masked value cannot be matched against the hashes of guessed values.
+	salt []byte
+}
+
+func newSecretMasker(patterns ...string) *secretMasker {
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	return &secretMasker{
+		patterns: append(append([]string{}, DefaultSecretPatterns...), patterns...),
+		salt:     salt,
+	}
+}
+
+// add adds the patterns declared in the agent runtime file.
+func (m *secretMasker) add(patterns ...string) {
+	if m == nil {
+		return
+	}
+	m.patterns = append(m.patterns, patterns...)
+}
+
+// addValue adds a value read from Secrets Manager. Values shorter than minSecretValueLength are ignored.
+func (m *secretMasker) addValue(v string) {
+	if m == nil || len(v) < minSecretValueLength {
+		return
+	}
+	if m.values == nil {
+		m.values = make(map[string]struct{})
+	}
+	m.values[v] = struct{}{}
+}
+
+// addKeysOfValues adds the names of the environment variables whose values are read from Secrets Manager.
+func (m *secretMasker) addKeysOfValues(env map[string]string) {
+	if m == nil {
+		return
+	}
+	for k, v := range env {
+		if _, ok := m.values[v]; !ok {
+			continue
+		}
+		if m.keys == nil {
+			m.keys = make(map[string]struct{})
+		}
+		m.keys[strings.ToUpper(k)] = struct{}{}
+	}
+}
+
+// isSecretKey reports whether the environment variable name matches any of the patterns or is sourced from Secrets Manager.
+func (m *secretMasker) isSecretKey(key string) bool {
+	if m == nil {
+		return false
+	}
+	upper := strings.ToUpper(key)
+	if _, ok := m.keys[upper]; ok {
+		return true
+	}
+	for _, p := range m.patterns {
+		if ok, _ := path.Match(strings.ToUpper(p), upper); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// maskValue replaces the value with a short salted hash, so that a diff still shows whether the value changed.
+func (m *secretMasker) maskValue(v string) string {
+	mac := hmac.New(sha256.New, m.salt)
+	mac.Write([]byte(v))
+	return "(masked:" + hex.EncodeToString(mac.Sum(nil))[:8] + ")"
+}
+
+// maskEnvironmentVariables returns a copy of the environment variables with secret values masked.
+func (m *secretMasker) maskEnvironmentVariables(env map[string]string) map[string]string {
+	if m == nil || env == nil {
+		return env
+	}
+	masked := make(map[string]string, len(env))
+	for k, v := range env {
+		if m.isSecretKey(k) {
+			v = m.maskValue(v)
+		}
+		masked[k] = v
+	}
+	return masked
+}
+
+// maskAgentRuntime returns a shallow copy of the agent runtime with secret environment variables masked.
+func (m *secretMasker) maskAgentRuntime(agentRuntime *AgentRuntime) *AgentRuntime {
+	if m == nil || agentRuntime == nil {
+		return agentRuntime
+	}
+	c := *agentRuntime
+	c.EnvironmentVariables = m.maskEnvironmentVariables(agentRuntime.EnvironmentVariables)
+	return &c
+}
+
+// maskDocument returns a copy of the JSON document with secret environment variables masked.
+// Any object under an "environmentVariables" key, at any depth and in any case, is masked.
+func (m *secretMasker) maskDocument(doc any) any {
+	if m == nil {
+		return doc
+	}
+	switch v := doc.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for k, e := range v {
+			if env, ok := e.(map[string]any); ok && strings.EqualFold(k, "environmentVariables") {
+				masked := make(map[string]any, len(env))
+				for name, value := range env {
+					if s, ok := value.(string); ok && m.isSecretKey(name) {
+						value = m.maskValue(s)
+					}
+					masked[name] = value
+				}
+				c[k] = masked
+				continue
+			}
+			c[k] = m.maskDocument(e)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, e := range v {
+			c[i] = m.maskDocument(e)
+		}
+		return c
+	default:
+		return doc
+	}
+}
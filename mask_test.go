@@ -0,0 +1,166 @@
+package acrun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSecretMasker_IsSecretKey(t *testing.T) {
+	m := newSecretMasker("*_DSN", "DATABASE_URL")
+	for key, expected := range map[string]bool{
+		"API_TOKEN":     true,
+		"client_secret": true,
+		"AWS_KEY_ID":    true,
+		"DB_PASSWORD":   true,
+		"SENTRY_DSN":    true,
+		"database_url":  true,
+		"env":           false,
+		"LOG_LEVEL":     false,
+	} {
+		require.Equal(t, expected, m.isSecretKey(key), key)
+	}
+
+	var nilMasker *secretMasker
+	require.False(t, nilMasker.isSecretKey("API_TOKEN"))
+}
+
+func TestSecretMasker_KeysOfValues(t *testing.T) {
+	m := newSecretMasker()
+	m.addValue("postgres://current")
+	// too short to identify the environment variables
+	m.addValue("true")
+	m.addKeysOfValues(map[string]string{
+		"db_url":  "postgres://current",
+		"FEATURE": "true",
+	})
+	require.True(t, m.isSecretKey("DB_URL"))
+	require.False(t, m.isSecretKey("FEATURE"))
+
+	// masked by name, not by value
+	require.Equal(t, map[string]string{
+		"DB_URL":  m.maskValue("postgres://rotated-out"),
+		"FEATURE": "true",
+		"COPY":    "postgres://current",
+	}, m.maskEnvironmentVariables(map[string]string{
+		"DB_URL":  "postgres://rotated-out",
+		"FEATURE": "true",
+		"COPY":    "postgres://current",
+	}))
+}
+
+func TestSecretMasker_MaskDocument(t *testing.T) {
+	m := newSecretMasker()
+	doc := map[string]any{
+		"AgentRuntimeName": "hosted_agent_dummy",
+		"EnvironmentVariables": map[string]any{
+			"env":       "dev",
+			"API_TOKEN": "token",
+		},
+	}
+	masked := m.maskDocument(doc).(map[string]any)
+	require.Equal(t, map[string]any{
+		"env":       "dev",
+		"API_TOKEN": m.maskValue("token"),
+	}, masked["EnvironmentVariables"])
+	// the original is not modified
+	require.Equal(t, "token", doc["EnvironmentVariables"].(map[string]any)["API_TOKEN"])
+	require.NotEqual(t, m.maskValue("token"), m.maskValue("token2"))
+	// salted per masker
+	require.NotEqual(t, m.maskValue("token"), newSecretMasker().maskValue("token"))
+
+	var nilMasker *secretMasker
+	require.Equal(t, doc, nilMasker.maskDocument(doc))
+}
+
+func TestRender_MaskSecrets(t *testing.T) {
+	app := &App{
+		logger:               slog.Default(),
+		agentRuntimeFilepath: "testdata/agent_runtime_with_secrets.json",
+		masker:               newSecretMasker(),
+	}
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+	_, err := app.Render(context.Background(), &RenderOption{Format: "json"})
+	require.NoError(t, err)
+	require.NotContains(t, stdout.String(), "super-secret-token")
+	require.NotContains(t, stdout.String(), "postgres://")
+	require.Contains(t, stdout.String(), app.masker.maskValue("super-secret-token"))
+	require.Contains(t, stdout.String(), `"env": "dev"`)
+
+	// --show-secrets
+	app.masker = nil
+	stdout.Reset()
+	_, err = app.Render(context.Background(), &RenderOption{Format: "json"})
+	require.NoError(t, err)
+	require.Contains(t, stdout.String(), "super-secret-token")
+}
+
+func TestDumpIfVerbose_MaskSecrets(t *testing.T) {
+	app := &App{
+		logger:  slog.Default(),
+		verbose: true,
+		masker:  newSecretMasker(),
+	}
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+	app.DumpIfVerbose(context.Background(), "CreateAgentRuntimeInput", &AgentRuntime{
+		AgentRuntimeName:     aws.String("hosted_agent_dummy"),
+		EnvironmentVariables: map[string]string{"API_TOKEN": "super-secret-token"},
+	})
+	require.Contains(t, stderr.String(), "CreateAgentRuntimeInput:")
+	require.NotContains(t, stderr.String(), "super-secret-token")
+	require.Contains(t, stderr.String(), app.masker.maskValue("super-secret-token"))
+}
+
+func TestDiff_MaskSecretsInJSON(t *testing.T) {
+	run := func(t *testing.T, opts *GlobalOption, diffOpt *DiffOption) string {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+		mockCtrlClient.EXPECT().
+			ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{}, nil).
+			AnyTimes()
+		app, err := NewWithClient(
+			context.Background(),
+			opts,
+			aws.Config{},
+			mockCtrlClient,
+			NewMockBedrockAgentCoreClient(ctrl),
+			NewMockECRClient(ctrl),
+			NewMockSTSClient(ctrl),
+			NewMockS3Client(ctrl),
+		)
+		require.NoError(t, err)
+		var stdout, stderr bytes.Buffer
+		app.SetOutput(&stdout, &stderr)
+		_, err = app.Diff(context.Background(), diffOpt)
+		require.NoError(t, err)
+		return stdout.String()
+	}
+
+	for _, diffOpt := range []*DiffOption{{Output: "json"}, {Format: "json"}, {Format: "json-patch"}} {
+		out := run(t, &GlobalOption{AgentRuntime: "testdata/agent_runtime_with_secrets.json"}, diffOpt)
+		require.NotContains(t, out, "super-secret-token", "%+v", diffOpt)
+		require.NotContains(t, out, "postgres://", "%+v", diffOpt)
+		require.Regexp(t, `\(masked:[0-9a-f]{8}\)`, out, "%+v", diffOpt)
+	}
+
+	// --show-secrets
+	var result DiffResult
+	out := run(t, &GlobalOption{AgentRuntime: "testdata/agent_runtime_with_secrets.json", ShowSecrets: true}, &DiffOption{Output: "json"})
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	require.Contains(t, result.Changes, DiffChange{Path: "/environmentVariables", Type: "added", New: map[string]any{
+		"env":          "dev",
+		"API_TOKEN":    "super-secret-token",
+		"DATABASE_URL": "postgres://user:pass@db/app",
+	}})
+}
@@ -0,0 +1,215 @@
+package acrun
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// CodeSource represents a local directory packaged as the codeConfiguration artifact.
+type CodeSource struct {
+	// Dir is the source directory. Relative paths are resolved from the agent runtime file.
+	Dir string `json:"dir"`
+	// Excludes is a list of glob patterns matched against the relative path and the base name.
+	Excludes []string `json:"excludes,omitempty"`
+}
+
+var defaultCodeSourceExcludes = []string{
+	".git",
+	".DS_Store",
+}
+
+// zipModTime is a fixed modification time for archive entries, so that the content hash is stable.
+var zipModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// packageCode zips the code source directory and rewrites codeConfiguration.code.s3 to the content-hash key.
+// The archive is uploaded only when upload is true and the key does not exist yet.
+func (app *App) packageCode(ctx context.Context, agentRuntime *AgentRuntime, src *CodeSource, upload bool) error {
+	code, err := codeS3Location(agentRuntime)
+	if err != nil {
+		return err
+	}
+	if src.Dir == "" {
+		return errors.New("codeSource.dir is required")
+	}
+	dir := app.resolveCodeSourceDir(src)
+	app.logger.InfoContext(ctx, "packaging code source", "dir", dir)
+	bs, sum, err := buildCodePackage(dir, src.Excludes)
+	if err != nil {
+		return err
+	}
+	bucket := aws.ToString(code.Value.Bucket)
+	key := path.Join(aws.ToString(code.Value.Prefix), sum+".zip")
+	code.Value.Prefix = aws.String(key)
+	code.Value.VersionId = nil
+	app.logger.DebugContext(ctx, "packaged code source", "dir", dir, "size", len(bs), "bucket", bucket, "key", key)
+	if !upload {
+		app.logger.DebugContext(ctx, "upload code package skipped", "bucket", bucket, "key", key)
+		return nil
+	}
+	return app.uploadCodePackage(ctx, bucket, key, bs)
+}
+
+// uploadPackagedCode uploads the code source for an agent runtime whose code key was already resolved by packageCode.
+// It fails when the content of the directory no longer matches the key.
+func (app *App) uploadPackagedCode(ctx context.Context, agentRuntime *AgentRuntime, src *CodeSource) error {
+	code, err := codeS3Location(agentRuntime)
+	if err != nil {
+		return err
+	}
+	bs, sum, err := buildCodePackage(src.Dir, src.Excludes)
+	if err != nil {
+		return err
+	}
+	key := aws.ToString(code.Value.Prefix)
+	if path.Base(key) != sum+".zip" {
+		return fmt.Errorf("code source %s has changed: expected key %s, but content hash is %s", src.Dir, key, sum)
+	}
+	return app.uploadCodePackage(ctx, aws.ToString(code.Value.Bucket), key, bs)
+}
+
+func codeS3Location(agentRuntime *AgentRuntime) (*types.CodeMemberS3, error) {
+	artifact, ok := agentRuntime.AgentRuntimeArtifact.(*types.AgentRuntimeArtifactMemberCodeConfiguration)
+	if !ok {
+		return nil, errors.New("codeSource requires agentRuntimeArtifact.codeConfiguration")
+	}
+	code, ok := artifact.Value.Code.(*types.CodeMemberS3)
+	if !ok {
+		return nil, errors.New("codeSource requires agentRuntimeArtifact.codeConfiguration.code.s3")
+	}
+	return code, nil
+}
+
+// resolveCodeSourceDir resolves a relative codeSource.dir from the agent runtime file.
+func (app *App) resolveCodeSourceDir(src *CodeSource) string {
+	if filepath.IsAbs(src.Dir) {
+		return src.Dir
+	}
+	return filepath.Join(filepath.Dir(app.agentRuntimeFilepath), src.Dir)
+}
+
+// buildCodePackage archives the directory and returns the archive with its hex encoded sha256.
+func buildCodePackage(dir string, excludes []string) ([]byte, string, error) {
+	bs, err := archiveDir(dir, append(append([]string{}, defaultCodeSourceExcludes...), excludes...))
+	if err != nil {
+		return nil, "", fmt.Errorf("archive %s: %w", dir, err)
+	}
+	sum := sha256.Sum256(bs)
+	return bs, hex.EncodeToString(sum[:]), nil
+}
+
+func (app *App) uploadCodePackage(ctx context.Context, bucket, key string, bs []byte) error {
+	if app.s3Client == nil {
+		return errors.New("S3 client is not available")
+	}
+	_, err := app.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err == nil {
+		app.logger.InfoContext(ctx, "code package already exists, skip uploading", "bucket", bucket, "key", key)
+		return nil
+	}
+	var nf *s3types.NotFound
+	if !errors.As(err, &nf) {
+		return fmt.Errorf("HeadObject: %w", err)
+	}
+	app.logger.InfoContext(ctx, "uploading code package", "bucket", bucket, "key", key, "size", len(bs))
+	if _, err := app.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(bs),
+		ContentType: aws.String("application/zip"),
+	}); err != nil {
+		return fmt.Errorf("PutObject: %w", err)
+	}
+	return nil
+}
+
+// archiveDir creates a zip archive of the directory.
+// Entries are written in lexical order with a fixed modification time, so the same content produces the same archive.
+func archiveDir(dir string, excludes []string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if isExcluded(rel, excludes) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		header.Method = zip.Deflate
+		header.Modified = zipModTime
+		fw, err := w.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(fw, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func isExcluded(rel string, excludes []string) bool {
+	for _, pattern := range excludes {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package acrun
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestArchiveDir(t *testing.T) {
+	bs, err := archiveDir("testdata/code_source", []string{"*.log"})
+	require.NoError(t, err)
+
+	r, err := zip.NewReader(bytes.NewReader(bs), int64(len(bs)))
+	require.NoError(t, err)
+	names := make([]string, 0, len(r.File))
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"main.py", "pkg/handler.py"}, names)
+
+	again, err := archiveDir("testdata/code_source", []string{"*.log"})
+	require.NoError(t, err)
+	require.Equal(t, bs, again, "archive should be deterministic")
+}
+
+func TestLoadAgentRuntimeFileWithExtension_CodeSource(t *testing.T) {
+	app := &App{
+		logger:               slog.Default(),
+		agentRuntimeFilepath: "testdata/agent_runtime_with_code_source.json",
+	}
+	def, ext, err := app.loadAgentRuntimeFileWithExtension(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &CodeSource{Dir: "code_source", Excludes: []string{"*.log"}}, ext.CodeSource)
+	require.Equal(t, &types.AgentRuntimeArtifactMemberCodeConfiguration{
+		Value: types.CodeConfiguration{
+			Code: &types.CodeMemberS3{
+				Value: types.S3Location{
+					Bucket: aws.String("example-bucket"),
+					Prefix: aws.String("acrun/hosted_agent_code"),
+				},
+			},
+			EntryPoint: []string{"main.py"},
+			Runtime:    types.AgentManagedRuntimeTypePython312,
+		},
+	}, def.AgentRuntimeArtifact)
+}
+
+func TestPackageCode(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Upload     bool
+		Exists     bool
+		ExpectPut  bool
+		ExpectHead bool
+	}{
+		{
+			Name:       "upload new package",
+			Upload:     true,
+			ExpectHead: true,
+			ExpectPut:  true,
+		},
+		{
+			Name:       "skip existing package",
+			Upload:     true,
+			Exists:     true,
+			ExpectHead: true,
+		},
+		{
+			Name: "no upload",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockS3Client := NewMockS3Client(ctrl)
+
+			var key string
+			if tc.ExpectHead {
+				mockS3Client.EXPECT().
+					HeadObject(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, in *s3.HeadObjectInput, _ ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+						require.Equal(t, "example-bucket", aws.ToString(in.Bucket))
+						key = aws.ToString(in.Key)
+						if tc.Exists {
+							return &s3.HeadObjectOutput{}, nil
+						}
+						return nil, &s3types.NotFound{}
+					})
+			}
+			if tc.ExpectPut {
+				mockS3Client.EXPECT().
+					PutObject(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, in *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+						require.Equal(t, key, aws.ToString(in.Key))
+						return &s3.PutObjectOutput{}, nil
+					})
+			}
+
+			app := &App{
+				logger:               slog.Default(),
+				agentRuntimeFilepath: "testdata/agent_runtime_with_code_source.json",
+				s3Client:             mockS3Client,
+			}
+			def, ext, err := app.loadAgentRuntimeFileWithExtension(context.Background())
+			require.NoError(t, err)
+			err = app.packageCode(context.Background(), def, ext.CodeSource, tc.Upload)
+			require.NoError(t, err)
+
+			artifact := def.AgentRuntimeArtifact.(*types.AgentRuntimeArtifactMemberCodeConfiguration)
+			prefix := aws.ToString(artifact.Value.Code.(*types.CodeMemberS3).Value.Prefix)
+			require.True(t, strings.HasPrefix(prefix, "acrun/hosted_agent_code/"), prefix)
+			require.True(t, strings.HasSuffix(prefix, ".zip"), prefix)
+			if tc.ExpectHead {
+				require.Equal(t, key, prefix)
+			}
+		})
+	}
+}
+
+func TestPackageCode_RequiresCodeConfiguration(t *testing.T) {
+	app := &App{
+		logger:               slog.Default(),
+		agentRuntimeFilepath: "testdata/agent_runtime.json",
+	}
+	def, err := app.loadAgentRuntimeFile(context.Background())
+	require.NoError(t, err)
+	err = app.packageCode(context.Background(), def, &CodeSource{Dir: "code_source"}, false)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "codeConfiguration")
+}
@@ -0,0 +1,116 @@
+package acrun
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const planFormatVersion = 1
+
+// Plan is a reviewable deploy plan written by `diff --out` and applied by `deploy --plan`.
+// It holds the fully rendered agent runtime, so that the applied input does not depend on
+// the Jsonnet evaluation at apply time.
+type Plan struct {
+	FormatVersion int       `json:"formatVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	// EndpointName is the endpoint that the plan deploys to.
+	EndpointName string `json:"endpointName"`
+	// RemoteARN and RemoteVersion are the remote agent runtime the plan was computed against.
+	// An empty RemoteVersion means the agent runtime did not exist.
+	RemoteARN     string `json:"remoteArn,omitempty"`
+	RemoteVersion string `json:"remoteVersion,omitempty"`
+	// AgentRuntime is the rendered agent runtime. The code key is already resolved when CodeSource is set.
+	AgentRuntime json.RawMessage `json:"agentRuntime"`
+	// CodeSource is the code source directory, resolved from the agent runtime file.
+	CodeSource *CodeSource `json:"codeSource,omitempty"`
+	// Endpoints is the endpoints declared in the agent runtime file.
+	Endpoints map[string]*EndpointConfig `json:"endpoints,omitempty"`
+	Diff      string                     `json:"diff"`
+}
+
+// ErrPlanStale is returned when the remote agent runtime has changed since the plan was created.
+var ErrPlanStale = errors.New("plan is stale")
+
+func newPlan(endpointName string, remoteARN, remoteVersion string, agentRuntime *AgentRuntime, src *CodeSource, endpoints map[string]*EndpointConfig, diff string) (*Plan, error) {
+	bs, err := marshalAgentRuntime(agentRuntime, "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshalAgentRuntime: %w", err)
+	}
+	return &Plan{
+		FormatVersion: planFormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		EndpointName:  endpointName,
+		RemoteARN:     remoteARN,
+		RemoteVersion: remoteVersion,
+		AgentRuntime:  bs,
+		CodeSource:    src,
+		Endpoints:     endpoints,
+		Diff:          diff,
+	}, nil
+}
+
+func (app *App) writePlan(ctx context.Context, path string, plan *Plan) error {
+	bs, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal plan: %w", err)
+	}
+	if err := os.WriteFile(path, append(bs, '\n'), 0644); err != nil {
+		return fmt.Errorf("write plan %s: %w", path, err)
+	}
+	app.logger.InfoContext(ctx, "wrote plan", "file", path, "endpoint", plan.EndpointName, "remote_version", plan.RemoteVersion)
+	return nil
+}
+
+func (app *App) loadPlan(ctx context.Context, path string) (*Plan, *AgentRuntime, error) {
+	app.logger.InfoContext(ctx, "loading plan", "file", path)
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read plan %s: %w", path, err)
+	}
+	var plan Plan
+	if err := json.Unmarshal(bs, &plan); err != nil {
+		return nil, nil, fmt.Errorf("decode plan %s: %w", path, err)
+	}
+	if plan.FormatVersion != planFormatVersion {
+		return nil, nil, fmt.Errorf("unsupported plan format version: %d", plan.FormatVersion)
+	}
+	if plan.EndpointName == "" {
+		return nil, nil, errors.New("plan has no endpoint name")
+	}
+	agentRuntime, err := unmarshalAgentRuntime(plan.AgentRuntime, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshalAgentRuntime: %w", err)
+	}
+	if err := validateAgentRuntime(agentRuntime); err != nil {
+		return nil, nil, err
+	}
+	if err := validateEndpointConfigs(plan.Endpoints); err != nil {
+		return nil, nil, err
+	}
+	return &plan, agentRuntime, nil
+}
+
+// checkPlan verifies that the remote agent runtime has not moved since the plan was created.
+func (app *App) checkPlan(ctx context.Context, plan *Plan, agentRuntime *AgentRuntime) error {
+	var remoteVersion string
+	out, err := app.getDeployBase(ctx, agentRuntime.AgentRuntimeName, aws.String(plan.EndpointName))
+	if err != nil {
+		if !errors.Is(err, ErrAgentRuntimeNotFound) {
+			return err
+		}
+	} else {
+		remoteVersion = aws.ToString(out.AgentRuntimeVersion)
+	}
+	if remoteVersion != plan.RemoteVersion {
+		return fmt.Errorf("%w: planned against version %q, but the remote is at version %q; run diff --out again",
+			ErrPlanStale, plan.RemoteVersion, remoteVersion)
+	}
+	app.logger.DebugContext(ctx, "plan is up to date", "endpoint", plan.EndpointName, "remote_version", remoteVersion)
+	return nil
+}
@@ -0,0 +1,163 @@
+package acrun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDiffOutAndDeployPlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	mockClient := NewMockBedrockAgentCoreClient(ctrl)
+	mockECRClient := NewMockECRClient(ctrl)
+	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
+
+	// Runtime not found (will create new)
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{}, nil).
+		AnyTimes()
+
+	local, err := (&App{agentRuntimeFilepath: "testdata/agent_runtime.json", logger: slog.Default()}).loadAgentRuntimeFile(context.Background())
+	require.NoError(t, err)
+	mockCtrlClient.EXPECT().
+		CreateAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, in *bedrockagentcorecontrol.CreateAgentRuntimeInput, _ ...func(*bedrockagentcorecontrol.Options)) (*bedrockagentcorecontrol.CreateAgentRuntimeOutput, error) {
+			require.Equal(t, local, in)
+			return &bedrockagentcorecontrol.CreateAgentRuntimeOutput{
+				AgentRuntimeId:      aws.String("new-runtime-id"),
+				AgentRuntimeVersion: aws.String("1"),
+			}, nil
+		})
+	mockCtrlClient.EXPECT().
+		GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+			AgentRuntimeId:      aws.String("new-runtime-id"),
+			AgentRuntimeVersion: aws.String("1"),
+			Status:              types.AgentRuntimeStatusReady,
+		}, nil)
+	mockCtrlClient.EXPECT().
+		GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, &types.ResourceNotFoundException{})
+	mockCtrlClient.EXPECT().
+		CreateAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, in *bedrockagentcorecontrol.CreateAgentRuntimeEndpointInput, _ ...func(*bedrockagentcorecontrol.Options)) (*bedrockagentcorecontrol.CreateAgentRuntimeEndpointOutput, error) {
+			require.Equal(t, "staging", aws.ToString(in.Name))
+			return &bedrockagentcorecontrol.CreateAgentRuntimeEndpointOutput{}, nil
+		})
+
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+		aws.Config{},
+		mockCtrlClient,
+		mockClient,
+		mockECRClient,
+		mockSTSClient,
+		mockS3Client,
+	)
+	require.NoError(t, err)
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+
+	_, err = app.Diff(context.Background(), &DiffOption{
+		Qualifier: aws.String("staging"),
+		Out:       planPath,
+	})
+	require.NoError(t, err)
+
+	plan, planned, err := app.loadPlan(context.Background(), planPath)
+	require.NoError(t, err)
+	require.Equal(t, "staging", plan.EndpointName)
+	require.Empty(t, plan.RemoteVersion)
+	require.NotEmpty(t, plan.Diff)
+	require.Equal(t, local, planned)
+
+	// the agent runtime file is not evaluated when applying the plan
+	app.agentRuntimeFilepath = "testdata/not_found.jsonnet"
+	_, err = app.Deploy(context.Background(), &DeployOption{
+		Plan:            planPath,
+		WaitDuration:    1 * time.Minute,
+		PollingInterval: 15 * time.Nanosecond,
+		NoWaitEndpoint:  true,
+	})
+	require.NoError(t, err)
+}
+
+func TestDeployPlan_Stale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	mockClient := NewMockBedrockAgentCoreClient(ctrl)
+	mockECRClient := NewMockECRClient(ctrl)
+	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
+
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+			AgentRuntimes: []types.AgentRuntime{
+				{
+					AgentRuntimeId:   aws.String("existing-runtime-id"),
+					AgentRuntimeName: aws.String("hosted_agent_dummy"),
+					AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/existing-runtime-id"),
+				},
+			},
+		}, nil)
+	mockCtrlClient.EXPECT().
+		GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+			TargetVersion: aws.String("3"),
+		}, nil)
+	mockCtrlClient.EXPECT().
+		GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+			AgentRuntimeId:      aws.String("existing-runtime-id"),
+			AgentRuntimeVersion: aws.String("3"),
+		}, nil)
+
+	local, err := (&App{agentRuntimeFilepath: "testdata/agent_runtime.json", logger: slog.Default()}).loadAgentRuntimeFile(context.Background())
+	require.NoError(t, err)
+	plan, err := newPlan("staging", "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/existing-runtime-id", "2", local, nil, nil, "")
+	require.NoError(t, err)
+
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+		aws.Config{},
+		mockCtrlClient,
+		mockClient,
+		mockECRClient,
+		mockSTSClient,
+		mockS3Client,
+	)
+	require.NoError(t, err)
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, app.writePlan(context.Background(), planPath, plan))
+
+	_, err = app.Deploy(context.Background(), &DeployOption{Plan: planPath, NoWaitEndpoint: true})
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrPlanStale), err.Error())
+
+	_, err = app.Deploy(context.Background(), &DeployOption{Plan: planPath, EndpointName: aws.String("prod"), NoWaitEndpoint: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not match the planned endpoint")
+}
@@ -0,0 +1,163 @@
+package acrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/go-jsonnet"
+)
+
+// DefaultProjectConfigFilenames are the project config files discovered from the current directory.
+var DefaultProjectConfigFilenames = []string{
+	"acrun.jsonnet",
+	"acrun.json",
+}
+
+// ProjectConfig is the project config file (acrun.jsonnet).
+// The top-level settings are the defaults, and each environment overrides them.
+type ProjectConfig struct {
+	ProjectSettings
+	Environments map[string]*ProjectSettings `json:"environments,omitempty"`
+}
+
+// ProjectSettings is the settings of the project or an environment.
+// The fields of GlobalOption use its json tags, e.g. agent_runtime, tfstate and ext_strs.
+type ProjectSettings struct {
+	GlobalOption
+	// EndpointName is the default of --endpoint-name for deploy, rollback and invoke, and of --qualifier for diff.
+	EndpointName string `json:"endpoint_name,omitempty"`
+	// Ignore is the default of --ignore for diff.
+	Ignore string `json:"ignore,omitempty"`
+}
+
+// FindProjectConfig returns the project config file in the directory, or an empty string if not found.
+func FindProjectConfig(dir string) string {
+	for _, fn := range DefaultProjectConfigFilenames {
+		path := filepath.Join(dir, fn)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// LoadProjectSettings loads the project config file and returns the settings of the environment.
+// An empty env returns the top-level settings. Relative paths in the settings are resolved from the directory of the file.
+func LoadProjectSettings(path string, env string) (*ProjectSettings, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read project config %s: %w", path, err)
+	}
+	bs := src
+	if filepath.Ext(path) == ".jsonnet" {
+		// native functions are not available; the AWS config depends on this file
+		out, err := jsonnet.MakeVM().EvaluateAnonymousSnippet(path, string(src))
+		if err != nil {
+			return nil, fmt.Errorf("evaluate project config %s: %w", path, err)
+		}
+		bs = []byte(out)
+	}
+	var cfg ProjectConfig
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("parse project config %s: %w", path, err)
+	}
+	settings := cfg.ProjectSettings
+	if env != "" {
+		override, ok := cfg.Environments[env]
+		if !ok {
+			return nil, fmt.Errorf("environment %q is not defined in %s (defined: %s)", env, path, strings.Join(slices.Sorted(maps.Keys(cfg.Environments)), ", "))
+		}
+		settings = mergeProjectSettings(settings, *override)
+	}
+	dir := filepath.Dir(path)
+	paths := []*string{&settings.AgentRuntime, &settings.ServerDefaults}
+	for i := range settings.JPath {
+		paths = append(paths, &settings.JPath[i])
+	}
+	for _, p := range paths {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+	for _, files := range []map[string]string{settings.TLAStrFile, settings.TLACodeFile} {
+		for k, p := range files {
+			if !filepath.IsAbs(p) {
+				files[k] = filepath.Join(dir, p)
+			}
+		}
+	}
+	return &settings, nil
+}
+
+// mergeProjectSettings returns base overridden by the non-empty fields of override. Ext vars are merged by key.
+func mergeProjectSettings(base, override ProjectSettings) ProjectSettings {
+	merged := override
+	merged.ExtStr = mergeStringMap(base.ExtStr, override.ExtStr)
+	merged.ExtCode = mergeStringMap(base.ExtCode, override.ExtCode)
+	merged.TLAStr = mergeStringMap(base.TLAStr, override.TLAStr)
+	merged.TLACode = mergeStringMap(base.TLACode, override.TLACode)
+	merged.TLAStrFile = mergeStringMap(base.TLAStrFile, override.TLAStrFile)
+	merged.TLACodeFile = mergeStringMap(base.TLACodeFile, override.TLACodeFile)
+	for _, f := range []struct{ dst, src *string }{
+		{&merged.AgentRuntime, &base.AgentRuntime},
+		{&merged.TFState, &base.TFState},
+		{&merged.Region, &base.Region},
+		{&merged.Profile, &base.Profile},
+		{&merged.ServerDefaults, &base.ServerDefaults},
+		{&merged.EndpointName, &base.EndpointName},
+		{&merged.Ignore, &base.Ignore},
+	} {
+		if *f.dst == "" {
+			*f.dst = *f.src
+		}
+	}
+	// Jsonnet searches the paths from the last, so the paths of override win
+	merged.JPath = append(slices.Clone(base.JPath), override.JPath...)
+	merged.Verbose = base.Verbose || override.Verbose
+	merged.ShowSecrets = base.ShowSecrets || override.ShowSecrets
+	return merged
+}
+
+// mergeStringMap returns a new map with the entries of base overridden by override.
+func mergeStringMap(base, override map[string]string) map[string]string {
+	if base == nil && override == nil {
+		return nil
+	}
+	m := make(map[string]string, len(base)+len(override))
+	maps.Copy(m, base)
+	maps.Copy(m, override)
+	return m
+}
+
+// Apply sets the settings to the empty fields of opts, so that explicit flags win.
+func (s *ProjectSettings) Apply(opts *GlobalOption) {
+	merged := mergeProjectSettings(ProjectSettings{GlobalOption: s.GlobalOption}, ProjectSettings{GlobalOption: *opts})
+	*opts = merged.GlobalOption
+}
+
+// loadProjectSettings loads the project config given by --config or discovered from the current directory.
+// It returns nil when there is no project config and no environment is specified.
+func loadProjectSettings(path, env string) (*ProjectSettings, error) {
+	if path == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("get current directory: %w", err)
+		}
+		path = FindProjectConfig(cwd)
+	}
+	if path == "" {
+		if env != "" {
+			return nil, fmt.Errorf("--env %s: project config not found (%s)", env, strings.Join(DefaultProjectConfigFilenames, ", "))
+		}
+		return nil, nil
+	}
+	return LoadProjectSettings(path, env)
+}
@@ -0,0 +1,72 @@
+package acrun
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProjectSettings(t *testing.T) {
+	path := "testdata/project/acrun.jsonnet"
+	require.Equal(t, filepath.Join("testdata/project", "acrun.jsonnet"), FindProjectConfig("testdata/project"))
+	require.Empty(t, FindProjectConfig("testdata"))
+
+	settings, err := LoadProjectSettings(path, "")
+	require.NoError(t, err)
+	require.Equal(t, "testdata/project/agent_runtime.jsonnet", settings.AgentRuntime)
+	require.Equal(t, "us-west-2", settings.Region)
+	require.Equal(t, "dev", settings.EndpointName)
+	require.Equal(t, map[string]string{"stage": "dev", "owner": "platform"}, settings.ExtStr)
+
+	settings, err = LoadProjectSettings(path, "prod")
+	require.NoError(t, err)
+	require.Equal(t, "testdata/project/agent_runtime_prod.jsonnet", settings.AgentRuntime)
+	require.Equal(t, "us-west-2", settings.Region)
+	require.Equal(t, "prod", settings.Profile)
+	require.Equal(t, "s3://example-bucket/terraform.tfstate", settings.TFState)
+	require.Equal(t, "prod", settings.EndpointName)
+	require.Equal(t, ".description", settings.Ignore)
+	require.Equal(t, map[string]string{"stage": "prod", "owner": "platform"}, settings.ExtStr)
+
+	_, err = LoadProjectSettings(path, "staging")
+	require.ErrorContains(t, err, `environment "staging" is not defined`)
+	require.ErrorContains(t, err, "prod")
+}
+
+func TestProjectSettings_JPath(t *testing.T) {
+	settings := &ProjectSettings{GlobalOption: GlobalOption{JPath: []string{"testdata/jpath/project"}}}
+	opts := &GlobalOption{JPath: []string{"testdata/jpath/cli"}}
+	settings.Apply(opts)
+	require.Equal(t, []string{"testdata/jpath/project", "testdata/jpath/cli"}, opts.JPath)
+
+	// the --jpath copy shadows the one in the project paths
+	vm := makeVM(context.Background(), slog.Default(), nil, nil, nil, nil, aws.Config{}, opts)
+	out, err := vm.EvaluateAnonymousSnippet("test.jsonnet", `(import 'shared.libsonnet').source`)
+	require.NoError(t, err)
+	require.Equal(t, "\"cli\"\n", out)
+}
+
+func TestCLI_ApplyProjectSettings(t *testing.T) {
+	settings, err := LoadProjectSettings("testdata/project/acrun.jsonnet", "prod")
+	require.NoError(t, err)
+
+	var c CLI
+	// explicit flags
+	c.Region = "ap-northeast-1"
+	c.ExtStr = map[string]string{"owner": "me"}
+	c.Rollback.EndpointName = aws.String("canary")
+	c.applyProjectSettings(settings)
+
+	require.Equal(t, "ap-northeast-1", c.Region)
+	require.Equal(t, "prod", c.Profile)
+	require.Equal(t, "testdata/project/agent_runtime_prod.jsonnet", c.AgentRuntime)
+	require.Equal(t, map[string]string{"stage": "prod", "owner": "me"}, c.ExtStr)
+	require.Equal(t, "prod", aws.ToString(c.Deploy.EndpointName))
+	require.Equal(t, "canary", aws.ToString(c.Rollback.EndpointName))
+	require.Equal(t, "prod", aws.ToString(c.Diff.Qualifier))
+	require.Equal(t, ".description", c.Diff.Ignore)
+}
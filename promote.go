@@ -0,0 +1,79 @@
+package acrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type PromoteOption struct {
+	DryRun bool   `name:"dry-run" help:"dry run" default:"false"`
+	From   string `name:"from" help:"the source endpoint name to take the version from" required:""`
+	To     string `name:"to" help:"the target endpoint name to point to the version. created if it does not exist" required:""`
+
+	NoWaitEndpoint       bool          `name:"no-wait-endpoint" help:"do not wait until the target endpoint is ready and serves the promoted version" default:"false"`
+	EndpointWaitDuration time.Duration `name:"endpoint-wait-duration" help:"maximum duration to wait until the target endpoint serves the promoted version" default:"10m"`
+	PollingInterval      time.Duration `name:"polling-interval" help:"polling interval to check the endpoint status" default:"5s"`
+
+	Output string `name:"output" short:"o" help:"output format (text, json)" default:"text" enum:"text,json"`
+}
+
+// Promote points the target endpoint to the version served by the source endpoint.
+// The agent runtime configuration is not changed, so no new version is created.
+func (app *App) Promote(ctx context.Context, opt *PromoteOption) (*PromoteResult, error) {
+	if opt.From == "" || opt.To == "" {
+		return nil, errors.New("both --from and --to are required")
+	}
+	if opt.To == DefaultEndpointName {
+		return nil, errors.New("promoting to the DEFAULT endpoint is not allowed")
+	}
+	if opt.From == opt.To {
+		return nil, fmt.Errorf("source and target endpoints are the same: %s", opt.From)
+	}
+	if opt.DryRun {
+		app.logger.WarnContext(ctx, "starting promote in DRY RUN mode. No changes will be made.")
+		defer app.logger.WarnContext(ctx, "ended promote in DRY RUN mode. No changes were made.")
+	}
+
+	agentRuntime, err := app.loadAgentRuntimeFile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load agent runtime file: %w", err)
+	}
+	id, err := app.GetAgentRuntimeIDByName(ctx, *agentRuntime.AgentRuntimeName)
+	if err != nil {
+		return nil, fmt.Errorf("get agent runtime ID: %w", err)
+	}
+	version, err := app.GetAgentRuntimeVersionByEndpointName(ctx, *agentRuntime.AgentRuntimeName, opt.From)
+	if err != nil {
+		return nil, fmt.Errorf("get version of endpoint %s: %w", opt.From, err)
+	}
+	if version == "" {
+		return nil, fmt.Errorf("endpoint %s has no version", opt.From)
+	}
+
+	app.logger.InfoContext(ctx, "promoting version", "from", opt.From, "to", opt.To, "version", version)
+	previousVersion, err := app.createOrUpdateAgentRuntimeEndpoint(ctx, id, opt.To, version, opt.DryRun)
+	if err != nil {
+		return nil, fmt.Errorf("createOrUpdateAgentRuntimeEndpoint: %w", err)
+	}
+	result := &PromoteResult{
+		AgentRuntimeName: *agentRuntime.AgentRuntimeName,
+		AgentRuntimeID:   id,
+		From:             opt.From,
+		To:               opt.To,
+		Version:          version,
+		PreviousVersion:  previousVersion,
+		DryRun:           opt.DryRun,
+	}
+	if opt.DryRun {
+		return result, app.writeResult(opt.Output, result)
+	}
+	if !opt.NoWaitEndpoint {
+		if err := app.waitForAgentRuntimeEndpoint(ctx, id, opt.To, version, opt.EndpointWaitDuration, opt.PollingInterval); err != nil {
+			return nil, fmt.Errorf("wait for agent runtime endpoint: %w", err)
+		}
+	}
+	app.logger.InfoContext(ctx, "promoted version", "from", opt.From, "to", opt.To, "version", version, "previous_version", previousVersion)
+	return result, app.writeResult(opt.Output, result)
+}
@@ -0,0 +1,171 @@
+package acrun
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPromote(t *testing.T) {
+	cases := []struct {
+		Name         string
+		DryRun       bool
+		TargetExists bool
+	}{
+		{
+			Name:         "update existing endpoint",
+			TargetExists: true,
+		},
+		{
+			Name: "create missing endpoint",
+		},
+		{
+			Name:         "dry run",
+			DryRun:       true,
+			TargetExists: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+			mockClient := NewMockBedrockAgentCoreClient(ctrl)
+			mockECRClient := NewMockECRClient(ctrl)
+			mockSTSClient := NewMockSTSClient(ctrl)
+			mockS3Client := NewMockS3Client(ctrl)
+
+			mockCtrlClient.EXPECT().
+				ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+					AgentRuntimes: []types.AgentRuntime{
+						{
+							AgentRuntimeId:   aws.String("test-runtime-id"),
+							AgentRuntimeName: aws.String("hosted_agent_dummy"),
+							AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+						},
+					},
+				}, nil)
+
+			calls := []any{
+				mockCtrlClient.EXPECT().
+					GetAgentRuntimeEndpoint(gomock.Any(), &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+						AgentRuntimeId: aws.String("test-runtime-id"),
+						EndpointName:   aws.String("staging"),
+					}).
+					Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+						LiveVersion:   aws.String("3"),
+						TargetVersion: aws.String("3"),
+					}, nil),
+			}
+			prodEndpoint := &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+				AgentRuntimeId: aws.String("test-runtime-id"),
+				EndpointName:   aws.String("prod"),
+			}
+			if tc.TargetExists {
+				calls = append(calls, mockCtrlClient.EXPECT().
+					GetAgentRuntimeEndpoint(gomock.Any(), prodEndpoint).
+					Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+						LiveVersion:   aws.String("1"),
+						TargetVersion: aws.String("1"),
+						Description:   aws.String("prod endpoint"),
+					}, nil))
+				if !tc.DryRun {
+					calls = append(calls, mockCtrlClient.EXPECT().
+						UpdateAgentRuntimeEndpoint(gomock.Any(), &bedrockagentcorecontrol.UpdateAgentRuntimeEndpointInput{
+							AgentRuntimeId:      aws.String("test-runtime-id"),
+							EndpointName:        aws.String("prod"),
+							AgentRuntimeVersion: aws.String("3"),
+							Description:         aws.String("prod endpoint"),
+						}).
+						Return(&bedrockagentcorecontrol.UpdateAgentRuntimeEndpointOutput{}, nil))
+				}
+			} else {
+				calls = append(calls,
+					mockCtrlClient.EXPECT().
+						GetAgentRuntimeEndpoint(gomock.Any(), prodEndpoint).
+						Return(nil, &types.ResourceNotFoundException{}),
+					mockCtrlClient.EXPECT().
+						CreateAgentRuntimeEndpoint(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ context.Context, in *bedrockagentcorecontrol.CreateAgentRuntimeEndpointInput, _ ...func(*bedrockagentcorecontrol.Options)) (*bedrockagentcorecontrol.CreateAgentRuntimeEndpointOutput, error) {
+							require.Equal(t, "prod", aws.ToString(in.Name))
+							require.Equal(t, "3", aws.ToString(in.AgentRuntimeVersion))
+							return &bedrockagentcorecontrol.CreateAgentRuntimeEndpointOutput{}, nil
+						}),
+				)
+			}
+			if !tc.DryRun {
+				calls = append(calls, mockCtrlClient.EXPECT().
+					GetAgentRuntimeEndpoint(gomock.Any(), prodEndpoint).
+					Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+						Status:        types.AgentRuntimeEndpointStatusReady,
+						LiveVersion:   aws.String("3"),
+						TargetVersion: aws.String("3"),
+					}, nil))
+			}
+			gomock.InOrder(calls...)
+
+			app, err := NewWithClient(
+				context.Background(),
+				&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+				aws.Config{},
+				mockCtrlClient,
+				mockClient,
+				mockECRClient,
+				mockSTSClient,
+				mockS3Client,
+			)
+			require.NoError(t, err)
+
+			var stdout, stderr bytes.Buffer
+			app.SetOutput(&stdout, &stderr)
+
+			_, err = app.Promote(context.Background(), &PromoteOption{
+				DryRun:               tc.DryRun,
+				From:                 "staging",
+				To:                   "prod",
+				EndpointWaitDuration: 1 * time.Minute,
+				PollingInterval:      15 * time.Nanosecond,
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPromote_InvalidEndpoints(t *testing.T) {
+	cases := []struct {
+		Name     string
+		From, To string
+		Expected string
+	}{
+		{
+			Name:     "DEFAULT target",
+			From:     "staging",
+			To:       DefaultEndpointName,
+			Expected: "DEFAULT endpoint is not allowed",
+		},
+		{
+			Name:     "same endpoint",
+			From:     "prod",
+			To:       "prod",
+			Expected: "source and target endpoints are the same",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			app := &App{agentRuntimeFilepath: "testdata/agent_runtime.json", logger: slog.Default()}
+			_, err := app.Promote(context.Background(), &PromoteOption{From: tc.From, To: tc.To})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.Expected)
+		})
+	}
+}
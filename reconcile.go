@@ -0,0 +1,250 @@
+package acrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+)
+
+// EndpointConfig is an endpoint declared in the `endpoints` section of the agent runtime file.
+type EndpointConfig struct {
+	Description *string `json:"description,omitempty"`
+	// Version selects the version the endpoint points to.
+	//   - "" (default): created with the deployed version, then left as is.
+	//   - "latest": always points to the deployed version.
+	//   - "pinned:<n>": always points to version n.
+	Version string `json:"version,omitempty"`
+}
+
+const (
+	endpointVersionLatest       = "latest"
+	endpointVersionPinnedPrefix = "pinned:"
+)
+
+// pinnedVersion returns the pinned version number, or an empty string if not pinned.
+func (c *EndpointConfig) pinnedVersion() string {
+	if c == nil {
+		return ""
+	}
+	v, ok := strings.CutPrefix(c.Version, endpointVersionPinnedPrefix)
+	if !ok {
+		return ""
+	}
+	return v
+}
+
+func validateEndpointConfigs(endpoints map[string]*EndpointConfig) error {
+	for name, c := range endpoints {
+		if name == DefaultEndpointName {
+			return errors.New("endpoints: the DEFAULT endpoint cannot be declared")
+		}
+		if c == nil {
+			continue
+		}
+		switch {
+		case c.Version == "", c.Version == endpointVersionLatest:
+		case strings.HasPrefix(c.Version, endpointVersionPinnedPrefix):
+			if _, err := strconv.ParseUint(c.pinnedVersion(), 10, 64); err != nil {
+				return fmt.Errorf("endpoints.%s.version: invalid pinned version %q", name, c.Version)
+			}
+		default:
+			return fmt.Errorf("endpoints.%s.version: must be empty, %q or %q, got %q", name, endpointVersionLatest, endpointVersionPinnedPrefix+"<n>", c.Version)
+		}
+	}
+	return nil
+}
+
+type endpointActionType string
+
+const (
+	endpointActionCreate     endpointActionType = "create"
+	endpointActionUpdate     endpointActionType = "update"
+	endpointActionDelete     endpointActionType = "delete"
+	endpointActionUndeclared endpointActionType = "undeclared"
+)
+
+// endpointAction is a change to reconcile an endpoint with the declaration.
+type endpointAction struct {
+	Type        endpointActionType
+	Name        string
+	Version     string
+	Description *string
+	// Changes describes what is updated, for reporting.
+	Changes []string
+}
+
+func (a endpointAction) String() string {
+	switch a.Type {
+	case endpointActionCreate:
+		return fmt.Sprintf("endpoint %s: create (version: %s)", a.Name, a.Version)
+	case endpointActionUpdate:
+		return fmt.Sprintf("endpoint %s: update %s", a.Name, strings.Join(a.Changes, ", "))
+	case endpointActionDelete:
+		return fmt.Sprintf("endpoint %s: delete", a.Name)
+	default:
+		return fmt.Sprintf("endpoint %s: exists remotely but is not declared (use --prune to delete)", a.Name)
+	}
+}
+
+// planEndpoints computes the actions to reconcile the remote endpoints with the declared endpoints.
+// id may be empty when the agent runtime does not exist yet.
+// deployEndpoint is the endpoint that deploy switches to deployedVersion; its version is managed by deploy.
+func (app *App) planEndpoints(ctx context.Context, id string, declared map[string]*EndpointConfig, deployedVersion string, deployEndpoint string, prune bool) ([]endpointAction, error) {
+	if c := declared[deployEndpoint]; c != nil && c.pinnedVersion() != "" {
+		return nil, fmt.Errorf("endpoints.%s: the deploy target endpoint cannot be pinned to a version", deployEndpoint)
+	}
+	remote := map[string]EndpointStatus{}
+	if id != "" {
+		endpoints, err := app.listAgentRuntimeEndpoints(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range endpoints {
+			remote[e.Name] = e
+		}
+	}
+
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var actions []endpointAction
+	for _, name := range names {
+		c := declared[name]
+		if c == nil {
+			c = &EndpointConfig{}
+		}
+		desired := deployedVersion
+		if v := c.pinnedVersion(); v != "" {
+			desired = v
+		}
+		current, exists := remote[name]
+		if !exists {
+			if name == deployEndpoint {
+				// deploy creates the endpoint
+				continue
+			}
+			actions = append(actions, endpointAction{
+				Type:        endpointActionCreate,
+				Name:        name,
+				Version:     desired,
+				Description: coalesce(c.Description, aws.String(fmt.Sprintf("Managed by %s", AppName))),
+			})
+			continue
+		}
+		action := endpointAction{
+			Type:    endpointActionUpdate,
+			Name:    name,
+			Version: coalesceString(current.TargetVersion, current.LiveVersion),
+		}
+		if name != deployEndpoint && (c.Version == endpointVersionLatest || c.pinnedVersion() != "") && action.Version != desired {
+			action.Changes = append(action.Changes, fmt.Sprintf("version %s -> %s", action.Version, desired))
+			action.Version = desired
+		}
+		if c.Description != nil && *c.Description != current.Description {
+			action.Description = c.Description
+			action.Changes = append(action.Changes, fmt.Sprintf("description %q -> %q", current.Description, *c.Description))
+		}
+		if len(action.Changes) > 0 {
+			if action.Description == nil && current.Description != "" {
+				action.Description = aws.String(current.Description)
+			}
+			actions = append(actions, action)
+		}
+	}
+
+	undeclared := make([]string, 0)
+	for name := range remote {
+		if _, ok := declared[name]; ok || name == DefaultEndpointName || name == deployEndpoint {
+			continue
+		}
+		undeclared = append(undeclared, name)
+	}
+	sort.Strings(undeclared)
+	for _, name := range undeclared {
+		t := endpointActionUndeclared
+		if prune {
+			t = endpointActionDelete
+		}
+		actions = append(actions, endpointAction{Type: t, Name: name})
+	}
+	return actions, nil
+}
+
+// reconcileEndpoints applies the actions computed by planEndpoints.
+func (app *App) reconcileEndpoints(ctx context.Context, id string, actions []endpointAction, dryRun bool, wait bool, waitDuration, interval time.Duration) error {
+	for _, a := range actions {
+		if a.Type == endpointActionUndeclared {
+			app.logger.WarnContext(ctx, "endpoint exists remotely but is not declared, use --prune to delete", "name", a.Name)
+			continue
+		}
+		app.logger.InfoContext(ctx, "reconciling endpoint", "action", string(a.Type), "name", a.Name, "version", a.Version)
+		if dryRun {
+			app.logger.DebugContext(ctx, "dry run: reconcile endpoint skipped", "name", a.Name)
+			continue
+		}
+		switch a.Type {
+		case endpointActionCreate:
+			if _, err := app.ctrlClient.CreateAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.CreateAgentRuntimeEndpointInput{
+				AgentRuntimeId:      aws.String(id),
+				Name:                aws.String(a.Name),
+				AgentRuntimeVersion: aws.String(a.Version),
+				Description:         a.Description,
+			}); err != nil {
+				return fmt.Errorf("CreateAgentRuntimeEndpoint(%s): %w", a.Name, err)
+			}
+		case endpointActionUpdate:
+			if _, err := app.ctrlClient.UpdateAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.UpdateAgentRuntimeEndpointInput{
+				AgentRuntimeId:      aws.String(id),
+				EndpointName:        aws.String(a.Name),
+				AgentRuntimeVersion: aws.String(a.Version),
+				Description:         a.Description,
+			}); err != nil {
+				return fmt.Errorf("UpdateAgentRuntimeEndpoint(%s): %w", a.Name, err)
+			}
+		case endpointActionDelete:
+			if _, err := app.ctrlClient.DeleteAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.DeleteAgentRuntimeEndpointInput{
+				AgentRuntimeId: aws.String(id),
+				EndpointName:   aws.String(a.Name),
+			}); err != nil {
+				return fmt.Errorf("DeleteAgentRuntimeEndpoint(%s): %w", a.Name, err)
+			}
+			if wait {
+				if err := app.waitForAgentRuntimeEndpointDeleted(ctx, id, a.Name, waitDuration, interval); err != nil {
+					return fmt.Errorf("wait for agent runtime endpoint deletion: %w", err)
+				}
+			}
+			continue
+		}
+		if wait {
+			if err := app.waitForAgentRuntimeEndpoint(ctx, id, a.Name, a.Version, waitDuration, interval); err != nil {
+				return fmt.Errorf("wait for agent runtime endpoint: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func writeEndpointActions(w io.Writer, actions []endpointAction) {
+	for _, a := range actions {
+		fmt.Fprintln(w, a.String())
+	}
+}
+
+func coalesceString(args ...string) string {
+	for _, arg := range args {
+		if arg != "" {
+			return arg
+		}
+	}
+	return ""
+}
@@ -0,0 +1,161 @@
+package acrun
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLoadAgentRuntimeFileWithExtension_Endpoints(t *testing.T) {
+	app := &App{
+		logger:               slog.Default(),
+		agentRuntimeFilepath: "testdata/agent_runtime_with_endpoints.json",
+	}
+	def, ext, err := app.loadAgentRuntimeFileWithExtension(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "hosted_agent_dummy", aws.ToString(def.AgentRuntimeName))
+	require.Equal(t, map[string]*EndpointConfig{
+		"current": {},
+		"staging": {Description: aws.String("staging environment"), Version: "latest"},
+		"prod":    {Version: "pinned:1"},
+	}, ext.Endpoints)
+}
+
+func TestValidateEndpointConfigs(t *testing.T) {
+	cases := []struct {
+		name      string
+		endpoints map[string]*EndpointConfig
+		wantErr   string
+	}{
+		{name: "empty", endpoints: nil},
+		{name: "valid", endpoints: map[string]*EndpointConfig{"a": nil, "b": {Version: "latest"}, "c": {Version: "pinned:3"}}},
+		{name: "default", endpoints: map[string]*EndpointConfig{"DEFAULT": {}}, wantErr: "DEFAULT endpoint cannot be declared"},
+		{name: "invalid pinned", endpoints: map[string]*EndpointConfig{"a": {Version: "pinned:x"}}, wantErr: "invalid pinned version"},
+		{name: "unknown version", endpoints: map[string]*EndpointConfig{"a": {Version: "3"}}, wantErr: "must be empty"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateEndpointConfigs(c.endpoints)
+			if c.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, c.wantErr)
+		})
+	}
+}
+
+func expectEndpoints(mockCtrlClient *MockBedrockAgentCoreControlClient, endpoints map[string]types.AgentRuntimeEndpoint) {
+	summaries := make([]types.AgentRuntimeEndpoint, 0, len(endpoints))
+	for name, e := range endpoints {
+		e.Name = aws.String(name)
+		summaries = append(summaries, e)
+	}
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimeEndpoints(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimeEndpointsOutput{RuntimeEndpoints: summaries}, nil)
+}
+
+func TestPlanEndpoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app, mockCtrlClient, _ := newEndpointTestApp(t, ctrl)
+
+	expectEndpoints(mockCtrlClient, map[string]types.AgentRuntimeEndpoint{
+		"DEFAULT": {LiveVersion: aws.String("3"), Status: types.AgentRuntimeEndpointStatusReady},
+		"current": {LiveVersion: aws.String("2"), Description: aws.String("Managed by acrun"), Status: types.AgentRuntimeEndpointStatusReady},
+		"staging": {LiveVersion: aws.String("2"), Description: aws.String("old"), Status: types.AgentRuntimeEndpointStatusReady},
+		"legacy":  {LiveVersion: aws.String("1"), Status: types.AgentRuntimeEndpointStatusReady},
+	})
+
+	declared := map[string]*EndpointConfig{
+		"current": {},
+		"staging": {Description: aws.String("staging environment"), Version: "latest"},
+		"prod":    {Version: "pinned:1"},
+	}
+	actions, err := app.planEndpoints(context.Background(), "test-runtime-id", declared, "3", "current", false)
+	require.NoError(t, err)
+	require.Equal(t, []endpointAction{
+		{
+			Type:        endpointActionCreate,
+			Name:        "prod",
+			Version:     "1",
+			Description: aws.String("Managed by acrun"),
+		},
+		{
+			Type:        endpointActionUpdate,
+			Name:        "staging",
+			Version:     "3",
+			Description: aws.String("staging environment"),
+			Changes:     []string{"version 2 -> 3", `description "old" -> "staging environment"`},
+		},
+		{
+			Type: endpointActionUndeclared,
+			Name: "legacy",
+		},
+	}, actions)
+}
+
+func TestPlanEndpoints_PinnedDeployEndpoint(t *testing.T) {
+	app := &App{logger: slog.Default()}
+	_, err := app.planEndpoints(context.Background(), "", map[string]*EndpointConfig{
+		"current": {Version: "pinned:1"},
+	}, "3", "current", false)
+	require.ErrorContains(t, err, "cannot be pinned")
+}
+
+func TestReconcileEndpoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app, mockCtrlClient, _ := newEndpointTestApp(t, ctrl)
+
+	mockCtrlClient.EXPECT().
+		CreateAgentRuntimeEndpoint(gomock.Any(), &bedrockagentcorecontrol.CreateAgentRuntimeEndpointInput{
+			AgentRuntimeId:      aws.String("test-runtime-id"),
+			Name:                aws.String("prod"),
+			AgentRuntimeVersion: aws.String("1"),
+			Description:         aws.String("Managed by acrun"),
+		}, gomock.Any()).
+		Return(&bedrockagentcorecontrol.CreateAgentRuntimeEndpointOutput{}, nil)
+	mockCtrlClient.EXPECT().
+		UpdateAgentRuntimeEndpoint(gomock.Any(), &bedrockagentcorecontrol.UpdateAgentRuntimeEndpointInput{
+			AgentRuntimeId:      aws.String("test-runtime-id"),
+			EndpointName:        aws.String("staging"),
+			AgentRuntimeVersion: aws.String("3"),
+			Description:         aws.String("staging environment"),
+		}, gomock.Any()).
+		Return(&bedrockagentcorecontrol.UpdateAgentRuntimeEndpointOutput{}, nil)
+	mockCtrlClient.EXPECT().
+		DeleteAgentRuntimeEndpoint(gomock.Any(), &bedrockagentcorecontrol.DeleteAgentRuntimeEndpointInput{
+			AgentRuntimeId: aws.String("test-runtime-id"),
+			EndpointName:   aws.String("legacy"),
+		}, gomock.Any()).
+		Return(&bedrockagentcorecontrol.DeleteAgentRuntimeEndpointOutput{}, nil)
+
+	err := app.reconcileEndpoints(context.Background(), "test-runtime-id", []endpointAction{
+		{Type: endpointActionCreate, Name: "prod", Version: "1", Description: aws.String("Managed by acrun")},
+		{Type: endpointActionUpdate, Name: "staging", Version: "3", Description: aws.String("staging environment")},
+		{Type: endpointActionDelete, Name: "legacy"},
+		{Type: endpointActionUndeclared, Name: "other"},
+	}, false, false, 0, 0)
+	require.NoError(t, err)
+}
+
+func TestReconcileEndpoints_DryRun(t *testing.T) {
+	// no API calls are expected
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app, _, _ := newEndpointTestApp(t, ctrl)
+
+	err := app.reconcileEndpoints(context.Background(), "test-runtime-id", []endpointAction{
+		{Type: endpointActionCreate, Name: "prod", Version: "1"},
+		{Type: endpointActionDelete, Name: "legacy"},
+	}, true, true, 0, 0)
+	require.NoError(t, err)
+}
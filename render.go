@@ -9,17 +9,22 @@ type RenderOption struct {
 	Format string `help:"output format (json, jsonnet)" default:"json" enum:"json,jsonnet"`
 }
 
-func (app *App) Render(ctx context.Context, opt *RenderOption) error {
+// Render returns the agent runtime loaded from the file. The printed configuration has secrets masked unless --show-secrets.
+func (app *App) Render(ctx context.Context, opt *RenderOption) (*AgentRuntime, error) {
 	agentRuntime, err := app.loadAgentRuntimeFile(ctx)
 	if err != nil {
-		return fmt.Errorf("load agent runtime file: %w", err)
+		return nil, fmt.Errorf("load agent runtime file: %w", err)
 	}
 
-	output, err := marshalAgentRuntime(agentRuntime, "  ")
+	return agentRuntime, app.renderAgentRuntime(agentRuntime, opt.Format)
+}
+
+func (app *App) renderAgentRuntime(agentRuntime *AgentRuntime, format string) error {
+	output, err := marshalAgentRuntime(app.masker.maskAgentRuntime(agentRuntime), "  ")
 	if err != nil {
 		return fmt.Errorf("marshal agent runtime: %w", err)
 	}
-	switch opt.Format {
+	switch format {
 	case "json":
 		// do nothing
 	case "jsonnet":
@@ -28,9 +33,9 @@ func (app *App) Render(ctx context.Context, opt *RenderOption) error {
 			return fmt.Errorf("convert to jsonnet: %w", err)
 		}
 	default:
-		return fmt.Errorf("unsupported format: %s", opt.Format)
+		return fmt.Errorf("unsupported format: %s", format)
 	}
 
-	fmt.Fprintln(app.stdout, string(output))
-	return nil
+	// the rendered configuration is the human-readable form in both formats
+	return app.writeResult("text", renderedAgentRuntime(output))
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"log/slog"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -27,6 +28,7 @@ func TestRender(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.Name, func(t *testing.T) {
 			app := &App{
+				logger:               slog.Default(),
 				agentRuntimeFilepath: "testdata/agent_runtime.json",
 			}
 			var stdout, stderr bytes.Buffer
@@ -36,7 +38,7 @@ func TestRender(t *testing.T) {
 				Format: tc.Format,
 			}
 
-			err := app.Render(context.Background(), opt)
+			_, err := app.Render(context.Background(), opt)
 			require.NoError(t, err)
 
 			output := stdout.String()
@@ -0,0 +1,248 @@
+package acrun
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DeployResult is the result of the deploy command.
+type DeployResult struct {
+	AgentRuntimeName string `json:"agentRuntimeName"`
+	AgentRuntimeID   string `json:"agentRuntimeId"`
+	AgentRuntimeArn  string `json:"agentRuntimeArn,omitempty"`
+	EndpointName     string `json:"endpointName"`
+	Version          string `json:"version"`
+	// PreviousVersion is the version the endpoint pointed to before the deploy. Empty when the endpoint was created.
+	PreviousVersion string `json:"previousVersion,omitempty"`
+	// Created reports whether the agent runtime was created.
+	Created bool `json:"created"`
+	// NewVersion reports whether a new version was created.
+	NewVersion bool          `json:"newVersion"`
+	DryRun     bool          `json:"dryRun"`
+	Phases     []PhaseTiming `json:"phases"`
+}
+
+// outputs returns the result as key=value pairs for --outputs-file.
+func (r *DeployResult) outputs() [][2]string {
+	return [][2]string{
+		{"agent_runtime_name", r.AgentRuntimeName},
+		{"agent_runtime_id", r.AgentRuntimeID},
+		{"agent_runtime_arn", r.AgentRuntimeArn},
+		{"endpoint_name", r.EndpointName},
+		{"version", r.Version},
+		{"previous_version", r.PreviousVersion},
+		{"created", strconv.FormatBool(r.Created)},
+		{"new_version", strconv.FormatBool(r.NewVersion)},
+		{"dry_run", strconv.FormatBool(r.DryRun)},
+	}
+}
+
+// VersionsListResult is the result of the versions list command.
+type VersionsListResult struct {
+	AgentRuntimeName string        `json:"agentRuntimeName"`
+	AgentRuntimeID   string        `json:"agentRuntimeId"`
+	Versions         []VersionInfo `json:"versions"`
+}
+
+func (r *VersionsListResult) writeText(w io.Writer) error {
+	return writeVersionsTable(w, r.Versions)
+}
+
+// EndpointListResult is the result of the endpoint list command.
+type EndpointListResult struct {
+	AgentRuntimeName string           `json:"agentRuntimeName"`
+	AgentRuntimeID   string           `json:"agentRuntimeId"`
+	Endpoints        []EndpointStatus `json:"endpoints"`
+}
+
+func (r *EndpointListResult) writeText(w io.Writer) error {
+	return writeEndpointsTable(w, r.Endpoints)
+}
+
+// EndpointCreateResult is the result of the endpoint create command.
+type EndpointCreateResult struct {
+	AgentRuntimeID string `json:"agentRuntimeId"`
+	EndpointName   string `json:"endpointName"`
+	// EndpointArn and Status are empty in dry run mode.
+	EndpointArn string `json:"endpointArn,omitempty"`
+	Version     string `json:"version"`
+	Status      string `json:"status,omitempty"`
+	DryRun      bool   `json:"dryRun"`
+}
+
+// EndpointDeleteResult is the result of the endpoint delete command.
+type EndpointDeleteResult struct {
+	AgentRuntimeID string `json:"agentRuntimeId"`
+	EndpointName   string `json:"endpointName"`
+	// EndpointArn, Version and Status are of the endpoint before the deletion. Empty when it was not found.
+	EndpointArn string `json:"endpointArn,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Status      string `json:"status,omitempty"`
+	// Deleted reports whether the endpoint was deleted; false when it was not found or the deletion was cancelled.
+	Deleted bool `json:"deleted"`
+	DryRun  bool `json:"dryRun"`
+}
+
+// ECRImagesResult is the result of the ecr-images command: the image URIs, sorted.
+type ECRImagesResult []string
+
+func (r ECRImagesResult) writeText(w io.Writer) error {
+	for _, image := range r {
+		fmt.Fprintln(w, image)
+	}
+	return nil
+}
+
+// renderedAgentRuntime is the agent runtime rendered by render and versions show.
+type renderedAgentRuntime []byte
+
+func (r renderedAgentRuntime) writeText(w io.Writer) error {
+	fmt.Fprintln(w, string(r))
+	return nil
+}
+
+// DiffResult is the result of the diff command.
+type DiffResult struct {
+	AgentRuntimeName string `json:"agentRuntimeName"`
+	Qualifier        string `json:"qualifier,omitempty"`
+	// From and To are the sources compared with --from/--to.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// RemoteArn and RemoteVersion are empty when the agent runtime does not exist.
+	RemoteArn     string `json:"remoteArn,omitempty"`
+	RemoteVersion string `json:"remoteVersion,omitempty"`
+	HasDiff       bool   `json:"hasDiff"`
+	Diff          string `json:"diff"`
+	// Changes is the changed paths. Secret values are masked unless --show-secrets is given.
+	Changes []DiffChange `json:"changes"`
+	// EndpointChanges is the changes to the declared endpoints.
+	EndpointChanges []string `json:"endpointChanges,omitempty"`
+	PlanFile        string   `json:"planFile,omitempty"`
+}
+
+// RollbackResult is the result of the rollback command.
+type RollbackResult struct {
+	AgentRuntimeName string `json:"agentRuntimeName"`
+	AgentRuntimeID   string `json:"agentRuntimeId"`
+	EndpointName     string `json:"endpointName"`
+	Version          string `json:"version"`
+	PreviousVersion  string `json:"previousVersion"`
+	// Changed reports whether the endpoint was switched; false when it already pointed to the version.
+	Changed bool `json:"changed"`
+	DryRun  bool `json:"dryRun"`
+}
+
+// PromoteResult is the result of the promote command.
+type PromoteResult struct {
+	AgentRuntimeName string `json:"agentRuntimeName"`
+	AgentRuntimeID   string `json:"agentRuntimeId"`
+	From             string `json:"from"`
+	To               string `json:"to"`
+	Version          string `json:"version"`
+	// PreviousVersion is the version the target endpoint pointed to. Empty when the endpoint was created.
+	PreviousVersion string `json:"previousVersion,omitempty"`
+	DryRun          bool   `json:"dryRun"`
+}
+
+// DeleteResult is the result of the delete command.
+type DeleteResult struct {
+	AgentRuntimeName string `json:"agentRuntimeName"`
+	AgentRuntimeID   string `json:"agentRuntimeId,omitempty"`
+	// Deleted reports whether the agent runtime was deleted; false when it was not found or the deletion was cancelled.
+	Deleted bool `json:"deleted"`
+	// Endpoints is the names of the deleted endpoints.
+	Endpoints []string `json:"endpoints"`
+	DryRun    bool     `json:"dryRun"`
+}
+
+// InitResult is the result of the init command.
+type InitResult struct {
+	AgentRuntimeName string `json:"agentRuntimeName"`
+	AgentRuntimeArn  string `json:"agentRuntimeArn"`
+	Version          string `json:"version"`
+	File             string `json:"file"`
+	// Written reports whether the file was written; false when overwriting the existing file was declined.
+	Written bool `json:"written"`
+}
+
+// InvokeResult is the result of the invoke command.
+type InvokeResult struct {
+	AgentRuntimeArn    string `json:"agentRuntimeArn"`
+	EndpointName       string `json:"endpointName"`
+	StatusCode         int32  `json:"statusCode"`
+	ContentType        string `json:"contentType,omitempty"`
+	RuntimeSessionID   string `json:"runtimeSessionId,omitempty"`
+	McpSessionID       string `json:"mcpSessionId,omitempty"`
+	McpProtocolVersion string `json:"mcpProtocolVersion,omitempty"`
+	TraceID            string `json:"traceId,omitempty"`
+	TraceParent        string `json:"traceParent,omitempty"`
+	TraceState         string `json:"traceState,omitempty"`
+	Baggage            string `json:"baggage,omitempty"`
+	// Response is the response body. It is embedded as is when it is valid JSON, otherwise as a string.
+	// Only filled with --output json; otherwise the body is streamed to stdout.
+	Response any `json:"response,omitempty"`
+}
+
+// PhaseTiming is the elapsed time of a phase of a command.
+type PhaseTiming struct {
+	Name       string    `json:"name"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+type phaseTimer struct {
+	phases []PhaseTiming
+}
+
+// start starts the phase and returns the function to end it.
+func (t *phaseTimer) start(name string) func() {
+	startedAt := time.Now()
+	return func() {
+		t.phases = append(t.phases, PhaseTiming{
+			Name:       name,
+			StartedAt:  startedAt.UTC(),
+			DurationMs: time.Since(startedAt).Milliseconds(),
+		})
+	}
+}
+
+// textResult is a result that has a human-readable form.
+type textResult interface {
+	writeText(w io.Writer) error
+}
+
+// writeResult writes the result to stdout as JSON when the output format is json.
+// Otherwise it writes the human-readable form of the result, if any; the others are reported by the logs.
+func (app *App) writeResult(output string, v any) error {
+	if output == "json" {
+		return writeJSON(app.stdout, v)
+	}
+	if t, ok := v.(textResult); ok {
+		return t.writeText(app.stdout)
+	}
+	return nil
+}
+
+// appendOutputsFile appends key=value lines to the file, in the format of $GITHUB_OUTPUT.
+func appendOutputsFile(path string, outputs [][2]string) error {
+	var b strings.Builder
+	for _, kv := range outputs {
+		if strings.ContainsAny(kv[1], "\r\n") {
+			return fmt.Errorf("output %s contains a newline", kv[0])
+		}
+		fmt.Fprintf(&b, "%s=%s\n", kv[0], kv[1])
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open outputs file %s: %w", path, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(b.String()); err != nil {
+		return fmt.Errorf("write outputs file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package acrun
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendOutputsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "github_output")
+	require.NoError(t, os.WriteFile(path, []byte("existing=value\n"), 0644))
+
+	require.NoError(t, appendOutputsFile(path, [][2]string{
+		{"version", "2"},
+		{"previous_version", ""},
+	}))
+	bs, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "existing=value\nversion=2\nprevious_version=\n", string(bs))
+
+	require.ErrorContains(t, appendOutputsFile(path, [][2]string{{"diff", "a\nb"}}), "contains a newline")
+}
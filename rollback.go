@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
@@ -14,28 +14,34 @@ type RollbackOption struct {
 	DryRun       bool    `name:"dry-run" help:"dry run" default:"false"`
 	EndpointName *string `name:"endpoint-name" help:"the endpoint name to rollback. if not specified, use the default endpoint."`
 	Version      *string `name:"version" help:"the version to rollback to. if not specified, rollback to current version - 1"`
+
+	NoWaitEndpoint       bool          `name:"no-wait-endpoint" help:"do not wait until the endpoint is ready and serves the rolled back version" default:"false"`
+	EndpointWaitDuration time.Duration `name:"endpoint-wait-duration" help:"maximum duration to wait until the endpoint serves the rolled back version" default:"10m"`
+	PollingInterval      time.Duration `name:"polling-interval" help:"polling interval to check the endpoint status" default:"5s"`
+
+	Output string `name:"output" short:"o" help:"output format (text, json)" default:"text" enum:"text,json"`
 }
 
-func (app *App) Rollback(ctx context.Context, opt *RollbackOption) error {
+func (app *App) Rollback(ctx context.Context, opt *RollbackOption) (*RollbackResult, error) {
 	e := fillEndpointName(opt.EndpointName)
 	if e == DefaultEndpointName {
-		return errors.New("rollback of the DEFAULT endpoint is not allowed")
+		return nil, errors.New("rollback of the DEFAULT endpoint is not allowed")
 	}
 	opt.EndpointName = &e
 
 	if opt.DryRun {
-		slog.WarnContext(ctx, "starting rollback in DRY RUN mode. No changes will be made.")
-		defer slog.WarnContext(ctx, "ended rollback in DRY RUN mode. No changes were made.")
+		app.logger.WarnContext(ctx, "starting rollback in DRY RUN mode. No changes will be made.")
+		defer app.logger.WarnContext(ctx, "ended rollback in DRY RUN mode. No changes were made.")
 	}
 
 	agentRuntime, err := app.loadAgentRuntimeFile(ctx)
 	if err != nil {
-		return fmt.Errorf("load agent runtime file: %w", err)
+		return nil, fmt.Errorf("load agent runtime file: %w", err)
 	}
 
 	id, err := app.GetAgentRuntimeIDByName(ctx, *agentRuntime.AgentRuntimeName)
 	if err != nil {
-		return fmt.Errorf("get agent runtime ID: %w", err)
+		return nil, fmt.Errorf("get agent runtime ID: %w", err)
 	}
 
 	// Get current endpoint
@@ -44,7 +50,7 @@ func (app *App) Rollback(ctx context.Context, opt *RollbackOption) error {
 		EndpointName:   opt.EndpointName,
 	})
 	if err != nil {
-		return fmt.Errorf("GetAgentRuntimeEndpoint: %w", err)
+		return nil, fmt.Errorf("GetAgentRuntimeEndpoint: %w", err)
 	}
 
 	currentVersionStr := aws.ToString(currentEndpoint.TargetVersion)
@@ -58,55 +64,78 @@ func (app *App) Rollback(ctx context.Context, opt *RollbackOption) error {
 		var currentVersionInt int
 		_, err := fmt.Sscanf(currentVersionStr, "%d", &currentVersionInt)
 		if err != nil {
-			return fmt.Errorf("failed to parse current version '%s' as integer: %w", currentVersionStr, err)
+			return nil, fmt.Errorf("failed to parse current version '%s' as integer: %w", currentVersionStr, err)
 		}
 		if currentVersionInt <= 1 {
-			return fmt.Errorf("cannot rollback: current version is %d (minimum is 1)", currentVersionInt)
+			return nil, fmt.Errorf("cannot rollback: current version is %d (minimum is 1)", currentVersionInt)
 		}
 		targetVersion = fmt.Sprintf("%d", currentVersionInt-1)
-		slog.InfoContext(ctx, "automatic rollback", "current", currentVersionStr, "target", targetVersion)
+		app.logger.InfoContext(ctx, "automatic rollback", "current", currentVersionStr, "target", targetVersion)
 	}
 
 	// Verify the target version exists
-	versionsOutput, err := app.ctrlClient.ListAgentRuntimeVersions(ctx, &bedrockagentcorecontrol.ListAgentRuntimeVersionsInput{
-		AgentRuntimeId: aws.String(id),
-	})
+	versions, err := app.listAgentRuntimeVersions(ctx, id)
 	if err != nil {
-		return fmt.Errorf("ListAgentRuntimeVersions: %w", err)
+		return nil, err
 	}
 
 	versionExists := false
-	for _, v := range versionsOutput.AgentRuntimes {
+	for _, v := range versions {
 		if aws.ToString(v.AgentRuntimeVersion) == targetVersion {
 			versionExists = true
 			break
 		}
 	}
 	if !versionExists {
-		return fmt.Errorf("version %s not found", targetVersion)
+		return nil, fmt.Errorf("version %s not found", targetVersion)
 	}
 
+	result := &RollbackResult{
+		AgentRuntimeName: *agentRuntime.AgentRuntimeName,
+		AgentRuntimeID:   id,
+		EndpointName:     *opt.EndpointName,
+		Version:          targetVersion,
+		PreviousVersion:  currentVersionStr,
+		DryRun:           opt.DryRun,
+	}
 	if currentVersionStr == targetVersion {
-		slog.InfoContext(ctx, "endpoint is already at the specified version", "endpoint", *opt.EndpointName, "version", targetVersion)
-		return nil
+		app.logger.InfoContext(ctx, "endpoint is already at the specified version", "endpoint", *opt.EndpointName, "version", targetVersion)
+		return result, app.writeResult(opt.Output, result)
 	}
 
-	slog.InfoContext(ctx, "rolling back endpoint", "endpoint", *opt.EndpointName, "from", currentVersionStr, "to", targetVersion)
+	app.logger.InfoContext(ctx, "rolling back endpoint", "endpoint", *opt.EndpointName, "from", currentVersionStr, "to", targetVersion)
+	result.Changed = true
 	if opt.DryRun {
-		slog.DebugContext(ctx, "dry run: rollback endpoint skipped")
-		return nil
+		app.logger.DebugContext(ctx, "dry run: rollback endpoint skipped")
+		return result, app.writeResult(opt.Output, result)
+	}
+
+	waitDuration := opt.EndpointWaitDuration
+	if opt.NoWaitEndpoint {
+		waitDuration = 0
 	}
+	if err := app.rollbackEndpoint(ctx, id, *opt.EndpointName, targetVersion, currentEndpoint.Description, waitDuration, opt.PollingInterval); err != nil {
+		return nil, err
+	}
+	return result, app.writeResult(opt.Output, result)
+}
 
-	_, err = app.ctrlClient.UpdateAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.UpdateAgentRuntimeEndpointInput{
+// rollbackEndpoint switches the endpoint back to the version, keeping the description, and waits until it serves the version.
+// A zero waitDuration skips the wait.
+func (app *App) rollbackEndpoint(ctx context.Context, id string, endpointName string, version string, description *string, waitDuration, pollingInterval time.Duration) error {
+	if _, err := app.ctrlClient.UpdateAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.UpdateAgentRuntimeEndpointInput{
 		AgentRuntimeId:      aws.String(id),
-		EndpointName:        opt.EndpointName,
-		AgentRuntimeVersion: aws.String(targetVersion),
-		Description:         currentEndpoint.Description,
-	})
-	if err != nil {
+		EndpointName:        aws.String(endpointName),
+		AgentRuntimeVersion: aws.String(version),
+		Description:         description,
+	}); err != nil {
 		return fmt.Errorf("UpdateAgentRuntimeEndpoint: %w", err)
 	}
-
-	slog.InfoContext(ctx, "rolled back endpoint", "endpoint", *opt.EndpointName, "version", targetVersion)
+	if waitDuration > 0 {
+		if err := app.waitForAgentRuntimeEndpoint(ctx, id, endpointName, version, waitDuration, pollingInterval); err != nil {
+			return fmt.Errorf("wait for agent runtime endpoint: %w", err)
+		}
+	}
+	app.logger.InfoContext(ctx, "rolled back endpoint", "endpoint", endpointName, "version", version)
 	return nil
 }
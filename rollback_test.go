@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
@@ -20,6 +21,7 @@ func TestRollback(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	endpointName := "test-endpoint"
 	targetVersion := "2"
@@ -41,7 +43,7 @@ func TestRollback(t *testing.T) {
 	mockCtrlClient.EXPECT().
 		ListAgentRuntimeVersions(gomock.Any(), &bedrockagentcorecontrol.ListAgentRuntimeVersionsInput{
 			AgentRuntimeId: aws.String("test-runtime-id"),
-		}).
+		}, gomock.Any()).
 		Return(&bedrockagentcorecontrol.ListAgentRuntimeVersionsOutput{
 			AgentRuntimes: []types.AgentRuntime{
 				{
@@ -83,19 +85,24 @@ func TestRollback(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 	var stdout, stderr bytes.Buffer
 	app.SetOutput(&stdout, &stderr)
 
 	opt := &RollbackOption{
-		DryRun:       false,
-		EndpointName: &endpointName,
-		Version:      &targetVersion,
+		NoWaitEndpoint: true,
+		DryRun:         false,
+		EndpointName:   &endpointName,
+		Version:        &targetVersion,
 	}
 
-	err = app.Rollback(context.Background(), opt)
+	result, err := app.Rollback(context.Background(), opt)
 	require.NoError(t, err)
+	require.Equal(t, targetVersion, result.Version)
+	require.Equal(t, "3", result.PreviousVersion)
+	require.True(t, result.Changed)
 }
 
 func TestRollback_VersionNotFound(t *testing.T) {
@@ -106,6 +113,7 @@ func TestRollback_VersionNotFound(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	endpointName := "test-endpoint"
 	targetVersion := "99" // Non-existent version
@@ -152,18 +160,20 @@ func TestRollback_VersionNotFound(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 	var stdout, stderr bytes.Buffer
 	app.SetOutput(&stdout, &stderr)
 
 	opt := &RollbackOption{
-		DryRun:       false,
-		EndpointName: &endpointName,
-		Version:      &targetVersion,
+		NoWaitEndpoint: true,
+		DryRun:         false,
+		EndpointName:   &endpointName,
+		Version:        &targetVersion,
 	}
 
-	err = app.Rollback(context.Background(), opt)
+	_, err = app.Rollback(context.Background(), opt)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "version 99 not found")
 }
@@ -176,6 +186,7 @@ func TestRollback_AlreadyAtVersion(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	endpointName := "test-endpoint"
 	targetVersion := "2"
@@ -197,7 +208,7 @@ func TestRollback_AlreadyAtVersion(t *testing.T) {
 	mockCtrlClient.EXPECT().
 		ListAgentRuntimeVersions(gomock.Any(), &bedrockagentcorecontrol.ListAgentRuntimeVersionsInput{
 			AgentRuntimeId: aws.String("test-runtime-id"),
-		}).
+		}, gomock.Any()).
 		Return(&bedrockagentcorecontrol.ListAgentRuntimeVersionsOutput{
 			AgentRuntimes: []types.AgentRuntime{
 				{
@@ -229,18 +240,20 @@ func TestRollback_AlreadyAtVersion(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 	var stdout, stderr bytes.Buffer
 	app.SetOutput(&stdout, &stderr)
 
 	opt := &RollbackOption{
-		DryRun:       false,
-		EndpointName: &endpointName,
-		Version:      &targetVersion,
+		NoWaitEndpoint: true,
+		DryRun:         false,
+		EndpointName:   &endpointName,
+		Version:        &targetVersion,
 	}
 
-	err = app.Rollback(context.Background(), opt)
+	_, err = app.Rollback(context.Background(), opt)
 	require.NoError(t, err)
 }
 
@@ -252,6 +265,7 @@ func TestRollback_DryRun(t *testing.T) {
 	mockClient := NewMockBedrockAgentCoreClient(ctrl)
 	mockECRClient := NewMockECRClient(ctrl)
 	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	endpointName := "test-endpoint"
 	targetVersion := "2"
@@ -273,7 +287,7 @@ func TestRollback_DryRun(t *testing.T) {
 	mockCtrlClient.EXPECT().
 		ListAgentRuntimeVersions(gomock.Any(), &bedrockagentcorecontrol.ListAgentRuntimeVersionsInput{
 			AgentRuntimeId: aws.String("test-runtime-id"),
-		}).
+		}, gomock.Any()).
 		Return(&bedrockagentcorecontrol.ListAgentRuntimeVersionsOutput{
 			AgentRuntimes: []types.AgentRuntime{
 				{
@@ -308,17 +322,101 @@ func TestRollback_DryRun(t *testing.T) {
 		mockClient,
 		mockECRClient,
 		mockSTSClient,
+		mockS3Client,
+	)
+	require.NoError(t, err)
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+
+	opt := &RollbackOption{
+		NoWaitEndpoint: true,
+		DryRun:         true,
+		EndpointName:   &endpointName,
+		Version:        &targetVersion,
+	}
+
+	_, err = app.Rollback(context.Background(), opt)
+	require.NoError(t, err)
+}
+
+func TestRollback_WaitEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	mockClient := NewMockBedrockAgentCoreClient(ctrl)
+	mockECRClient := NewMockECRClient(ctrl)
+	mockSTSClient := NewMockSTSClient(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
+
+	endpointName := "test-endpoint"
+
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+			AgentRuntimes: []types.AgentRuntime{
+				{
+					AgentRuntimeId:   aws.String("test-runtime-id"),
+					AgentRuntimeName: aws.String("hosted_agent_dummy"),
+					AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+				},
+			},
+		}, nil)
+
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimeVersions(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimeVersionsOutput{
+			AgentRuntimes: []types.AgentRuntime{
+				{AgentRuntimeVersion: aws.String("1")},
+				{AgentRuntimeVersion: aws.String("2")},
+			},
+		}, nil)
+
+	gomock.InOrder(
+		mockCtrlClient.EXPECT().
+			GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+				TargetVersion: aws.String("2"),
+				Description:   aws.String("Test endpoint"),
+			}, nil),
+		mockCtrlClient.EXPECT().
+			GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+				Status:      types.AgentRuntimeEndpointStatusUpdating,
+				LiveVersion: aws.String("2"),
+			}, nil),
+		mockCtrlClient.EXPECT().
+			GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any()).
+			Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+				Status:      types.AgentRuntimeEndpointStatusReady,
+				LiveVersion: aws.String("1"),
+			}, nil),
+	)
+
+	mockCtrlClient.EXPECT().
+		UpdateAgentRuntimeEndpoint(gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.UpdateAgentRuntimeEndpointOutput{}, nil)
+
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+		aws.Config{},
+		mockCtrlClient,
+		mockClient,
+		mockECRClient,
+		mockSTSClient,
+		mockS3Client,
 	)
 	require.NoError(t, err)
 	var stdout, stderr bytes.Buffer
 	app.SetOutput(&stdout, &stderr)
 
 	opt := &RollbackOption{
-		DryRun:       true,
-		EndpointName: &endpointName,
-		Version:      &targetVersion,
+		EndpointName:         &endpointName,
+		EndpointWaitDuration: 1 * time.Minute,
+		PollingInterval:      15 * time.Nanosecond,
 	}
 
-	err = app.Rollback(context.Background(), opt)
+	_, err = app.Rollback(context.Background(), opt)
 	require.NoError(t, err)
 }
@@ -0,0 +1,102 @@
+package acrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/google/go-jsonnet"
+	"github.com/google/go-jsonnet/ast"
+)
+
+// secretVersionIDPattern matches the version IDs generated by Secrets Manager.
+// Other versions are treated as staging labels such as AWSCURRENT and AWSPREVIOUS.
+var secretVersionIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// secretsManagerNativeFuncs returns the secretsManager and secretsManagerVersion native functions.
+// The secret strings are cached for the VM, and the returned strings are added to the masker.
+func secretsManagerNativeFuncs(ctx context.Context, client SecretsManagerClient, masker *secretMasker) []*jsonnet.NativeFunction {
+	cache := &sync.Map{}
+	lookup := func(name string, secretID, version, jsonKey any) (any, error) {
+		if client == nil {
+			return nil, fmt.Errorf("%s: Secrets Manager client is not available", name)
+		}
+		id, ok := secretID.(string)
+		if !ok || id == "" {
+			return nil, fmt.Errorf("%s: secretId must be a non-empty string", name)
+		}
+		v, _ := version.(string)
+		key, ok := jsonKey.(string)
+		if !ok && jsonKey != nil {
+			return nil, fmt.Errorf("%s: jsonKey must be a string or null", name)
+		}
+		s, err := getSecretString(ctx, client, cache, id, v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if key == "" {
+			masker.addValue(s)
+			return s, nil
+		}
+		var doc map[string]any
+		if err := json.Unmarshal([]byte(s), &doc); err != nil {
+			return nil, fmt.Errorf("%s: secret %s is not a JSON object: %w", name, id, err)
+		}
+		value, ok := doc[key]
+		if !ok {
+			return nil, fmt.Errorf("%s: key %s is not found in secret %s", name, key, id)
+		}
+		if str, ok := value.(string); ok {
+			masker.addValue(str)
+		}
+		return value, nil
+	}
+	return []*jsonnet.NativeFunction{
+		{
+			Name:   "secretsManager",
+			Params: []ast.Identifier{"secretId", "jsonKey"},
+			Func: func(args []any) (any, error) {
+				return lookup("secretsManager", args[0], "", args[1])
+			},
+		},
+		{
+			Name:   "secretsManagerVersion",
+			Params: []ast.Identifier{"secretId", "version", "jsonKey"},
+			Func: func(args []any) (any, error) {
+				if v, ok := args[1].(string); !ok || v == "" {
+					return nil, fmt.Errorf("secretsManagerVersion: version must be a non-empty string")
+				}
+				return lookup("secretsManagerVersion", args[0], args[1], args[2])
+			},
+		},
+	}
+}
+
+// getSecretString returns the secret string of the version. An empty version is the current version.
+func getSecretString(ctx context.Context, client SecretsManagerClient, cache *sync.Map, secretID, version string) (string, error) {
+	cacheKey := secretID + "\x00" + version
+	if v, ok := cache.Load(cacheKey); ok {
+		return v.(string), nil
+	}
+	input := &secretsmanager.GetSecretValueInput{SecretId: aws.String(secretID)}
+	switch {
+	case version == "":
+	case secretVersionIDPattern.MatchString(version):
+		input.VersionId = aws.String(version)
+	default:
+		input.VersionStage = aws.String(version)
+	}
+	output, err := client.GetSecretValue(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret value %s: %w", secretID, err)
+	}
+	if output.SecretString == nil {
+		return "", fmt.Errorf("secret %s has no secret string", secretID)
+	}
+	cache.Store(cacheKey, *output.SecretString)
+	return *output.SecretString, nil
+}
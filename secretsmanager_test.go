@@ -0,0 +1,199 @@
+package acrun
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSecretsManager(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := NewMockSecretsManagerClient(ctrl)
+	// cached per secret and version
+	client.EXPECT().GetSecretValue(gomock.Any(), &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String("acrun/app"),
+	}).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"clientId":"client-123","dbUrl":"postgres://current"}`),
+	}, nil).Times(1)
+	client.EXPECT().GetSecretValue(gomock.Any(), &secretsmanager.GetSecretValueInput{
+		SecretId:     aws.String("acrun/app"),
+		VersionStage: aws.String("AWSPREVIOUS"),
+	}).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"clientId":"client-123","dbUrl":"postgres://previous"}`),
+	}, nil).Times(1)
+	client.EXPECT().GetSecretValue(gomock.Any(), &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String("acrun/raw"),
+	}).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String("raw-value"),
+	}, nil).Times(1)
+
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime_with_secrets_manager.jsonnet"},
+		aws.Config{},
+		nil, nil, nil, nil, nil,
+		WithSecretsManagerClient(client),
+		WithLogger(slog.Default()),
+	)
+	require.NoError(t, err)
+
+	agentRuntime, err := app.loadAgentRuntimeFile(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"env":             "dev",
+		"DB_URL":          "postgres://current",
+		"PREVIOUS_DB_URL": "postgres://previous",
+		"RAW":             "raw-value",
+	}, agentRuntime.EnvironmentVariables)
+
+	// the values read from Secrets Manager are masked even if the names do not look secret
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+	_, err = app.Render(context.Background(), &RenderOption{Format: "json"})
+	require.NoError(t, err)
+	require.NotContains(t, stdout.String(), "postgres://")
+	require.NotContains(t, stdout.String(), "raw-value")
+	require.Contains(t, stdout.String(), app.masker.maskValue("postgres://current"))
+	require.Contains(t, stdout.String(), `"env": "dev"`)
+}
+
+func TestSecretsManager_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := NewMockSecretsManagerClient(ctrl)
+	client.EXPECT().GetSecretValue(gomock.Any(), &secretsmanager.GetSecretValueInput{
+		SecretId:  aws.String("acrun/app"),
+		VersionId: aws.String("a1b2c3d4-90ab-cdef-fedc-ba987654321f"),
+	}).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"clientId":"client-123"}`),
+	}, nil).AnyTimes()
+	client.EXPECT().GetSecretValue(gomock.Any(), &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String("acrun/binary"),
+	}).Return(&secretsmanager.GetSecretValueOutput{
+		SecretBinary: []byte("binary"),
+	}, nil).AnyTimes()
+
+	vm := makeVM(context.Background(), slog.Default(), nil, nil, client, nil, aws.Config{}, &GlobalOption{})
+	cases := []struct {
+		snippet string
+		err     string
+	}{
+		{`std.native('secretsManagerVersion')('acrun/app', 'a1b2c3d4-90ab-cdef-fedc-ba987654321f', 'missing')`, "key missing is not found in secret acrun/app"},
+		{`std.native('secretsManager')('acrun/binary', null)`, "secret acrun/binary has no secret string"},
+		{`std.native('secretsManager')('', null)`, "secretId must be a non-empty string"},
+		{`std.native('secretsManagerVersion')('acrun/app', '', null)`, "version must be a non-empty string"},
+	}
+	for _, c := range cases {
+		_, err := vm.EvaluateAnonymousSnippet("test.jsonnet", c.snippet)
+		require.ErrorContains(t, err, c.err, c.snippet)
+	}
+
+	out, err := vm.EvaluateAnonymousSnippet("test.jsonnet", `std.native('secretsManagerVersion')('acrun/app', 'a1b2c3d4-90ab-cdef-fedc-ba987654321f', 'clientId')`)
+	require.NoError(t, err)
+	require.Equal(t, "\"client-123\"\n", out)
+
+	vm = makeVM(context.Background(), slog.Default(), nil, nil, nil, nil, aws.Config{}, &GlobalOption{})
+	_, err = vm.EvaluateAnonymousSnippet("test.jsonnet", `std.native('secretsManager')('acrun/app', null)`)
+	require.ErrorContains(t, err, "Secrets Manager client is not available")
+}
+
+func TestSecretsManager_DiffRotated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := NewMockSecretsManagerClient(ctrl)
+	client.EXPECT().GetSecretValue(gomock.Any(), &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String("acrun/app"),
+	}).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"clientId":"client-123","dbUrl":"postgres://current"}`),
+	}, nil).AnyTimes()
+	client.EXPECT().GetSecretValue(gomock.Any(), &secretsmanager.GetSecretValueInput{
+		SecretId:     aws.String("acrun/app"),
+		VersionStage: aws.String("AWSPREVIOUS"),
+	}).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(`{"clientId":"client-123","dbUrl":"postgres://previous"}`),
+	}, nil).AnyTimes()
+	client.EXPECT().GetSecretValue(gomock.Any(), &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String("acrun/raw"),
+	}).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String("raw-value"),
+	}, nil).AnyTimes()
+
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+			AgentRuntimes: []types.AgentRuntime{
+				{
+					AgentRuntimeId:   aws.String("test-runtime-id"),
+					AgentRuntimeName: aws.String("hosted_agent_dummy"),
+					AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+				},
+			},
+		}, nil).AnyTimes()
+	mockCtrlClient.EXPECT().
+		GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+			TargetVersion: aws.String("1"),
+		}, nil).AnyTimes()
+	// the remote still has the values before the rotation
+	mockCtrlClient.EXPECT().
+		GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+			AgentRuntimeId:      aws.String("test-runtime-id"),
+			AgentRuntimeName:    aws.String("hosted_agent_dummy"),
+			AgentRuntimeVersion: aws.String("1"),
+			RoleArn:             aws.String("arn:aws:iam::123456789012:role/service-role/DummyServiceRole"),
+			EnvironmentVariables: map[string]string{
+				"env":             "dev",
+				"DB_URL":          "postgres://rotated-out",
+				"PREVIOUS_DB_URL": "postgres://older",
+				"RAW":             "old-raw-value",
+			},
+		}, nil).AnyTimes()
+
+	run := func(opts *GlobalOption, diffOpt *DiffOption) (*App, string) {
+		app, err := NewWithClient(
+			context.Background(),
+			opts,
+			aws.Config{},
+			mockCtrlClient,
+			NewMockBedrockAgentCoreClient(ctrl),
+			NewMockECRClient(ctrl),
+			NewMockSTSClient(ctrl),
+			NewMockS3Client(ctrl),
+			WithSecretsManagerClient(client),
+		)
+		require.NoError(t, err)
+		var stdout, stderr bytes.Buffer
+		app.SetOutput(&stdout, &stderr)
+		_, err = app.Diff(context.Background(), diffOpt)
+		require.NoError(t, err)
+		return app, stdout.String()
+	}
+
+	for _, diffOpt := range []*DiffOption{{}, {Output: "json"}} {
+		app, out := run(&GlobalOption{AgentRuntime: "testdata/agent_runtime_with_secrets_manager.jsonnet"}, diffOpt)
+		for _, v := range []string{"postgres://", "raw-value"} {
+			require.NotContains(t, out, v, diffOpt.Output)
+		}
+		require.Contains(t, out, app.masker.maskValue("postgres://rotated-out"), diffOpt.Output)
+		require.Contains(t, out, app.masker.maskValue("postgres://current"), diffOpt.Output)
+	}
+
+	// --show-secrets
+	_, out := run(&GlobalOption{AgentRuntime: "testdata/agent_runtime_with_secrets_manager.jsonnet", ShowSecrets: true}, &DiffOption{})
+	require.Contains(t, out, "postgres://rotated-out")
+	require.Contains(t, out, "postgres://current")
+}
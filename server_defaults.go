@@ -0,0 +1,121 @@
+package acrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// builtinServerDefaults are the values the service populates when the fields are omitted.
+// Numbers are float64 as they are compared with decoded JSON.
+var builtinServerDefaults = map[string]any{
+	"lifecycleConfiguration": map[string]any{
+		"idleRuntimeSessionTimeout": float64(900),
+		"maxLifetime":               float64(28800),
+	},
+	"protocolConfiguration": map[string]any{
+		"serverProtocol": "HTTP",
+	},
+}
+
+// serverDefault is a field that the service populates with value when it is omitted.
+type serverDefault struct {
+	path  []string
+	value any
+}
+
+// newServerDefaults flattens the defaults document into rules. Each non-object value is a default.
+func newServerDefaults(docs ...map[string]any) []serverDefault {
+	var rules []serverDefault
+	var walk func(prefix []string, doc map[string]any)
+	walk = func(prefix []string, doc map[string]any) {
+		for k, v := range doc {
+			path := append(slices.Clone(prefix), k)
+			if m, ok := v.(map[string]any); ok {
+				walk(path, m)
+				continue
+			}
+			rules = append(rules, serverDefault{path: path, value: v})
+		}
+	}
+	for _, doc := range docs {
+		walk(nil, doc)
+	}
+	return rules
+}
+
+// serverDefaults returns the built-in server defaults and the ones in --server-defaults.
+func (app *App) serverDefaults(ctx context.Context) ([]serverDefault, error) {
+	if app.serverDefaultsFilepath == "" {
+		return newServerDefaults(builtinServerDefaults), nil
+	}
+	bs, err := app.readJSONOrJsonnetFile(app.serverDefaultsFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("load server defaults: %w", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(bs, &doc); err != nil {
+		return nil, fmt.Errorf("parse server defaults %s: %w", app.serverDefaultsFilepath, err)
+	}
+	app.logger.DebugContext(ctx, "loaded server defaults", "file", app.serverDefaultsFilepath)
+	return newServerDefaults(builtinServerDefaults, doc), nil
+}
+
+// suppressServerDefaults removes the fields that are present on only one side with the server default value,
+// then removes nulls and empty objects from both sides, so that null, an empty object and a missing field are equivalent.
+func suppressServerDefaults(from, to any, rules []serverDefault) (any, any) {
+	for _, rule := range rules {
+		fromValue, inFrom := lookupPath(from, rule.path)
+		toValue, inTo := lookupPath(to, rule.path)
+		switch {
+		case inFrom && !inTo && reflect.DeepEqual(fromValue, rule.value):
+			deletePath(from, rule.path)
+		case inTo && !inFrom && reflect.DeepEqual(toValue, rule.value):
+			deletePath(to, rule.path)
+		}
+	}
+	return pruneEmpty(from), pruneEmpty(to)
+}
+
+func lookupPath(doc any, path []string) (any, bool) {
+	for _, k := range path {
+		m, ok := doc.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if doc, ok = m[k]; !ok {
+			return nil, false
+		}
+	}
+	return doc, true
+}
+
+func deletePath(doc any, path []string) {
+	parent, ok := lookupPath(doc, path[:len(path)-1])
+	if !ok {
+		return
+	}
+	if m, ok := parent.(map[string]any); ok {
+		delete(m, path[len(path)-1])
+	}
+}
+
+// pruneEmpty removes null values and empty objects from the objects in doc, recursively.
+// The root is kept as is.
+func pruneEmpty(doc any) any {
+	m, ok := doc.(map[string]any)
+	if !ok {
+		return doc
+	}
+	for k, v := range m {
+		v = pruneEmpty(v)
+		if child, ok := v.(map[string]any); v == nil || ok && len(child) == 0 {
+			delete(m, k)
+			continue
+		}
+		m[k] = v
+	}
+	return m
+}
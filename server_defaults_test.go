@@ -0,0 +1,87 @@
+package acrun
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressServerDefaults(t *testing.T) {
+	remote := map[string]any{
+		"agentRuntimeName": "hosted_agent_dummy",
+		"lifecycleConfiguration": map[string]any{
+			"idleRuntimeSessionTimeout": float64(900),
+			"maxLifetime":               float64(3600),
+		},
+		"protocolConfiguration": map[string]any{
+			"serverProtocol": "HTTP",
+		},
+		"environmentVariables": map[string]any{},
+		"description":          nil,
+	}
+	local := map[string]any{
+		"agentRuntimeName": "hosted_agent_dummy",
+		"lifecycleConfiguration": map[string]any{
+			"idleRuntimeSessionTimeout": float64(900),
+		},
+	}
+	from, to := suppressServerDefaults(remote, local, newServerDefaults(builtinServerDefaults))
+	require.Equal(t, map[string]any{
+		"agentRuntimeName": "hosted_agent_dummy",
+		"lifecycleConfiguration": map[string]any{
+			// declared locally, so compared
+			"idleRuntimeSessionTimeout": float64(900),
+			// not the default
+			"maxLifetime": float64(3600),
+		},
+	}, from)
+	require.Equal(t, local, to)
+}
+
+func TestServerDefaults_File(t *testing.T) {
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime.json", ServerDefaults: "testdata/server_defaults.jsonnet"},
+		aws.Config{},
+		nil, nil, nil, nil, nil,
+		WithLogger(slog.Default()),
+	)
+	require.NoError(t, err)
+	defaults, err := app.serverDefaults(context.Background())
+	require.NoError(t, err)
+
+	remote := &AgentRuntime{
+		AgentRuntimeName: aws.String("hosted_agent_dummy"),
+		Description:      aws.String(""),
+	}
+	local := &AgentRuntime{
+		AgentRuntimeName: aws.String("hosted_agent_dummy"),
+	}
+	diff, err := diffAgentRuntime(remote, local, "remote", "local", defaults)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+
+	diff, err = diffAgentRuntime(remote, local, "remote", "local", newServerDefaults(builtinServerDefaults))
+	require.NoError(t, err)
+	require.Contains(t, diff, `-  "description": ""`)
+
+	// the declared default of requestHeaderConfiguration.allowList
+	remote.RequestHeaderConfiguration = &types.RequestHeaderConfigurationMemberRequestHeaderAllowlist{Value: []string{}}
+	diff, err = diffAgentRuntime(remote, local, "remote", "local", defaults)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+
+	diff, err = diffAgentRuntime(remote, local, "remote", "local", newServerDefaults(builtinServerDefaults))
+	require.NoError(t, err)
+	require.Contains(t, diff, `"allowList": []`)
+
+	// a non-default value is still reported
+	remote.RequestHeaderConfiguration = &types.RequestHeaderConfigurationMemberRequestHeaderAllowlist{Value: []string{"X-Request-ID"}}
+	diff, err = diffAgentRuntime(remote, local, "remote", "local", defaults)
+	require.NoError(t, err)
+	require.Contains(t, diff, `"X-Request-ID"`)
+}
@@ -0,0 +1,115 @@
+package acrun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/itchyny/gojq"
+)
+
+// SmokeTestCase represents a single case in the smoke test file.
+type SmokeTestCase struct {
+	Name string `json:"name"`
+	// Payload is sent as is when it is a string, otherwise it is encoded as JSON.
+	Payload     any     `json:"payload"`
+	ContentType *string `json:"contentType,omitempty"`
+	Accept      *string `json:"accept,omitempty"`
+	// Assert is a jq expression evaluated against the response body.
+	// The case passes when the first result is neither false nor null.
+	Assert string `json:"assert"`
+}
+
+func (app *App) loadSmokeTestCases(path string) ([]SmokeTestCase, error) {
+	bs, err := app.readJSONOrJsonnetFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cases []SmokeTestCase
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cases); err != nil {
+		return nil, fmt.Errorf("decode smoke test cases %s: %w", path, err)
+	}
+	for i, c := range cases {
+		if c.Assert == "" {
+			return nil, fmt.Errorf("smoke test case #%d (%s): assert is required", i, c.Name)
+		}
+		if c.Name == "" {
+			cases[i].Name = fmt.Sprintf("case-%d", i)
+		}
+	}
+	return cases, nil
+}
+
+// runSmokeTest invokes the agent runtime endpoint with each case and checks the responses.
+func (app *App) runSmokeTest(ctx context.Context, arn string, endpointName string, cases []SmokeTestCase) error {
+	var failed []string
+	for _, c := range cases {
+		if err := app.runSmokeTestCase(ctx, arn, endpointName, c); err != nil {
+			app.logger.ErrorContext(ctx, "smoke test case failed", "case", c.Name, "error", err)
+			failed = append(failed, c.Name)
+			continue
+		}
+		app.logger.InfoContext(ctx, "smoke test case passed", "case", c.Name)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d smoke test cases failed: %v", len(failed), len(cases), failed)
+	}
+	return nil
+}
+
+func (app *App) runSmokeTestCase(ctx context.Context, arn string, endpointName string, c SmokeTestCase) error {
+	query, err := gojq.Parse(c.Assert)
+	if err != nil {
+		return fmt.Errorf("parse assert %q: %w", c.Assert, err)
+	}
+	var payload []byte
+	switch p := c.Payload.(type) {
+	case string:
+		payload = []byte(p)
+	default:
+		payload, err = json.Marshal(p)
+		if err != nil {
+			return fmt.Errorf("marshal payload: %w", err)
+		}
+	}
+	resp, err := app.invokeAgentRuntime(ctx, arn, payload, &InvokeOption{
+		EndpointName: aws.String(endpointName),
+		ContentType:  c.ContentType,
+		Accept:       c.Accept,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Response.Close()
+	body, err := io.ReadAll(resp.Response)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	app.DumpIfVerbose(ctx, "SmokeTestResponse("+c.Name+")", string(body))
+	var input any
+	if err := json.Unmarshal(body, &input); err != nil {
+		// not a JSON response; assert against the raw string
+		input = string(body)
+	}
+	iter := query.RunWithContext(ctx, input)
+	v, ok := iter.Next()
+	if !ok {
+		return fmt.Errorf("assert %q returned no result", c.Assert)
+	}
+	if err, ok := v.(error); ok {
+		return fmt.Errorf("assert %q: %w", c.Assert, err)
+	}
+	if v == nil || v == false {
+		return fmt.Errorf("assert %q is not satisfied", c.Assert)
+	}
+	return nil
+}
+
+// ErrSmokeTestFailed is returned when the smoke test after deploy fails.
+var ErrSmokeTestFailed = errors.New("smoke test failed")
@@ -0,0 +1,187 @@
+package acrun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcore"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeploy_SmokeTest(t *testing.T) {
+	cases := []struct {
+		Name         string
+		ResponseBody string
+		ShouldErr    bool
+	}{
+		{
+			Name:         "passed",
+			ResponseBody: `{"result": "pong"}`,
+		},
+		{
+			Name:         "failed and rolled back",
+			ResponseBody: `{"result": "error"}`,
+			ShouldErr:    true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+			mockClient := NewMockBedrockAgentCoreClient(ctrl)
+			mockECRClient := NewMockECRClient(ctrl)
+			mockSTSClient := NewMockSTSClient(ctrl)
+			mockS3Client := NewMockS3Client(ctrl)
+
+			endpointName := "test-endpoint"
+
+			mockCtrlClient.EXPECT().
+				ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+					AgentRuntimes: []types.AgentRuntime{
+						{
+							AgentRuntimeId:   aws.String("existing-runtime-id"),
+							AgentRuntimeName: aws.String("hosted_agent_dummy"),
+							AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/existing-runtime-id"),
+						},
+					},
+				}, nil)
+
+			endpointCalls := []any{
+				mockCtrlClient.EXPECT().
+					GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+						TargetVersion: aws.String("1"),
+					}, nil),
+				mockCtrlClient.EXPECT().
+					GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+						TargetVersion: aws.String("1"),
+						Description:   aws.String("Existing endpoint"),
+					}, nil),
+				mockCtrlClient.EXPECT().
+					GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+						Status:        types.AgentRuntimeEndpointStatusReady,
+						LiveVersion:   aws.String("2"),
+						TargetVersion: aws.String("2"),
+					}, nil),
+			}
+			updateCalls := []any{
+				mockCtrlClient.EXPECT().
+					UpdateAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, in *bedrockagentcorecontrol.UpdateAgentRuntimeEndpointInput, _ ...func(*bedrockagentcorecontrol.Options)) (*bedrockagentcorecontrol.UpdateAgentRuntimeEndpointOutput, error) {
+						require.Equal(t, "2", aws.ToString(in.AgentRuntimeVersion))
+						return &bedrockagentcorecontrol.UpdateAgentRuntimeEndpointOutput{}, nil
+					}),
+			}
+			if tc.ShouldErr {
+				// rollback to the previous version keeping the description, and wait until the endpoint serves it
+				endpointCalls = append(endpointCalls,
+					mockCtrlClient.EXPECT().
+						GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+							Status:        types.AgentRuntimeEndpointStatusReady,
+							LiveVersion:   aws.String("2"),
+							TargetVersion: aws.String("2"),
+							Description:   aws.String("Existing endpoint"),
+						}, nil),
+					mockCtrlClient.EXPECT().
+						GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+							Status:        types.AgentRuntimeEndpointStatusReady,
+							LiveVersion:   aws.String("1"),
+							TargetVersion: aws.String("1"),
+						}, nil),
+				)
+				updateCalls = append(updateCalls, mockCtrlClient.EXPECT().
+					UpdateAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, in *bedrockagentcorecontrol.UpdateAgentRuntimeEndpointInput, _ ...func(*bedrockagentcorecontrol.Options)) (*bedrockagentcorecontrol.UpdateAgentRuntimeEndpointOutput, error) {
+						require.Equal(t, "existing-runtime-id", aws.ToString(in.AgentRuntimeId))
+						require.Equal(t, endpointName, aws.ToString(in.EndpointName))
+						require.Equal(t, "1", aws.ToString(in.AgentRuntimeVersion))
+						require.Equal(t, "Existing endpoint", aws.ToString(in.Description))
+						return &bedrockagentcorecontrol.UpdateAgentRuntimeEndpointOutput{}, nil
+					}))
+			}
+			gomock.InOrder(endpointCalls...)
+			gomock.InOrder(updateCalls...)
+
+			gomock.InOrder(
+				mockCtrlClient.EXPECT().
+					GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+						AgentRuntimeId:      aws.String("existing-runtime-id"),
+						AgentRuntimeVersion: aws.String("1"),
+					}, nil),
+				mockCtrlClient.EXPECT().
+					UpdateAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&bedrockagentcorecontrol.UpdateAgentRuntimeOutput{
+						AgentRuntimeId:      aws.String("existing-runtime-id"),
+						AgentRuntimeVersion: aws.String("2"),
+					}, nil),
+				mockCtrlClient.EXPECT().
+					GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+						AgentRuntimeId:      aws.String("existing-runtime-id"),
+						AgentRuntimeVersion: aws.String("2"),
+						Status:              types.AgentRuntimeStatusReady,
+					}, nil),
+			)
+
+			mockClient.EXPECT().
+				InvokeAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, in *bedrockagentcore.InvokeAgentRuntimeInput, _ ...func(*bedrockagentcore.Options)) (*bedrockagentcore.InvokeAgentRuntimeOutput, error) {
+					require.Equal(t, endpointName, aws.ToString(in.Qualifier))
+					require.JSONEq(t, `{"inputText": "ping"}`, string(in.Payload))
+					return &bedrockagentcore.InvokeAgentRuntimeOutput{
+						StatusCode:  aws.Int32(200),
+						ContentType: aws.String("application/json"),
+						Response:    io.NopCloser(bytes.NewBufferString(tc.ResponseBody)),
+					}, nil
+				})
+
+			app, err := NewWithClient(
+				context.Background(),
+				&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+				aws.Config{},
+				mockCtrlClient,
+				mockClient,
+				mockECRClient,
+				mockSTSClient,
+				mockS3Client,
+			)
+			require.NoError(t, err)
+
+			var stdout, stderr bytes.Buffer
+			app.SetOutput(&stdout, &stderr)
+
+			opt := &DeployOption{
+				EndpointName:         &endpointName,
+				WaitDuration:         1 * time.Minute,
+				PollingInterval:      15 * time.Nanosecond,
+				EndpointWaitDuration: 1 * time.Minute,
+				SmokeTest:            "testdata/smoke_test.jsonnet",
+			}
+
+			_, err = app.Deploy(context.Background(), opt)
+			if !tc.ShouldErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.True(t, errors.Is(err, ErrSmokeTestFailed))
+			require.Contains(t, err.Error(), "rolled back endpoint test-endpoint to version 1")
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package acrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+)
+
+// StatusOption represents the options for the status command.
+type StatusOption struct {
+	Output   string        `name:"output" short:"o" help:"output format (text, json)" default:"text" enum:"text,json"`
+	Watch    bool          `name:"watch" short:"w" help:"refresh the status until interrupted" default:"false"`
+	Interval time.Duration `name:"interval" help:"refresh interval for --watch" default:"5s"`
+}
+
+// Status is the status of the agent runtime and its endpoints.
+type Status struct {
+	AgentRuntimeName string           `json:"agentRuntimeName"`
+	AgentRuntimeID   string           `json:"agentRuntimeId"`
+	AgentRuntimeArn  string           `json:"agentRuntimeArn"`
+	LatestVersion    string           `json:"latestVersion"`
+	Status           string           `json:"status"`
+	ContainerURI     string           `json:"containerUri,omitempty"`
+	LastUpdatedAt    *time.Time       `json:"lastUpdatedAt,omitempty"`
+	Endpoints        []EndpointStatus `json:"endpoints"`
+}
+
+// EndpointStatus is the status of an agent runtime endpoint.
+type EndpointStatus struct {
+	Name               string     `json:"name"`
+	Arn                string     `json:"arn,omitempty"`
+	Description        string     `json:"description,omitempty"`
+	Status             string     `json:"status"`
+	LiveVersion        string     `json:"liveVersion,omitempty"`
+	TargetVersion      string     `json:"targetVersion,omitempty"`
+	LiveContainerURI   string     `json:"liveContainerUri,omitempty"`
+	TargetContainerURI string     `json:"targetContainerUri,omitempty"`
+	FailureReason      string     `json:"failureReason,omitempty"`
+	CreatedAt          *time.Time `json:"createdAt,omitempty"`
+	LastUpdatedAt      *time.Time `json:"lastUpdatedAt,omitempty"`
+}
+
+func newEndpointStatus(name string, out *bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput) EndpointStatus {
+	return EndpointStatus{
+		Name:          name,
+		Arn:           aws.ToString(out.AgentRuntimeEndpointArn),
+		Description:   aws.ToString(out.Description),
+		Status:        string(out.Status),
+		LiveVersion:   aws.ToString(out.LiveVersion),
+		TargetVersion: aws.ToString(out.TargetVersion),
+		FailureReason: aws.ToString(out.FailureReason),
+		CreatedAt:     out.CreatedAt,
+		LastUpdatedAt: out.LastUpdatedAt,
+	}
+}
+
+// Status returns the status of the agent runtime. With --watch, it returns the last status when interrupted.
+func (app *App) Status(ctx context.Context, opt *StatusOption) (*Status, error) {
+	agentRuntime, err := app.loadAgentRuntimeFile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load agent runtime file: %w", err)
+	}
+	name := *agentRuntime.AgentRuntimeName
+	if !opt.Watch {
+		status, err := app.getStatus(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return status, app.writeResult(opt.Output, status)
+	}
+
+	interval := opt.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last *Status
+	for {
+		status, err := app.getStatus(ctx, name)
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, nil
+			}
+			return nil, err
+		}
+		last = status
+		if opt.Output == "text" {
+			// clear the screen and move the cursor to the top-left
+			fmt.Fprint(app.stdout, "\033[H\033[2J")
+			fmt.Fprintf(app.stdout, "Every %s: %s\n\n", interval, time.Now().Format(time.RFC3339))
+		}
+		if err := app.writeResult(opt.Output, status); err != nil {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return last, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (app *App) getStatus(ctx context.Context, name string) (*Status, error) {
+	id, err := app.GetAgentRuntimeIDByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("get agent runtime ID: %w", err)
+	}
+	// without a version, GetAgentRuntime returns the latest version
+	latest, err := app.ctrlClient.GetAgentRuntime(ctx, &bedrockagentcorecontrol.GetAgentRuntimeInput{
+		AgentRuntimeId: aws.String(id),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetAgentRuntime: %w", err)
+	}
+	status := &Status{
+		AgentRuntimeName: name,
+		AgentRuntimeID:   id,
+		AgentRuntimeArn:  aws.ToString(latest.AgentRuntimeArn),
+		LatestVersion:    aws.ToString(latest.AgentRuntimeVersion),
+		Status:           string(latest.Status),
+		ContainerURI:     extractContainerURI(latest.AgentRuntimeArtifact),
+		LastUpdatedAt:    latest.LastUpdatedAt,
+		Endpoints:        []EndpointStatus{},
+	}
+
+	containerURIs := map[string]string{
+		status.LatestVersion: status.ContainerURI,
+	}
+	containerURI := func(version string) string {
+		if version == "" {
+			return ""
+		}
+		if uri, ok := containerURIs[version]; ok {
+			return uri
+		}
+		uri, err := app.getContainerURIForVersion(ctx, id, version)
+		if err != nil {
+			app.logger.WarnContext(ctx, "failed to get container URI for version", "version", version, "error", err)
+		}
+		containerURIs[version] = uri
+		return uri
+	}
+
+	endpoints, err := app.listAgentRuntimeEndpoints(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, es := range endpoints {
+		es.LiveContainerURI = containerURI(es.LiveVersion)
+		es.TargetContainerURI = containerURI(es.TargetVersion)
+		status.Endpoints = append(status.Endpoints, es)
+	}
+	return status, nil
+}
+
+// listAgentRuntimeEndpoints returns the endpoints with their versions, sorted by name.
+// Container URIs are not filled, as the summaries do not include them.
+// Failed endpoints are read with GetAgentRuntimeEndpoint for their failure reasons.
+func (app *App) listAgentRuntimeEndpoints(ctx context.Context, id string) ([]EndpointStatus, error) {
+	var endpoints []EndpointStatus
+	p := bedrockagentcorecontrol.NewListAgentRuntimeEndpointsPaginator(app.ctrlClient, &bedrockagentcorecontrol.ListAgentRuntimeEndpointsInput{
+		AgentRuntimeId: aws.String(id),
+	})
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ListAgentRuntimeEndpoints: %w", err)
+		}
+		for _, endpoint := range out.RuntimeEndpoints {
+			name := aws.ToString(endpoint.Name)
+			if strings.HasSuffix(string(endpoint.Status), "_FAILED") {
+				e, err := app.ctrlClient.GetAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+					AgentRuntimeId: aws.String(id),
+					EndpointName:   endpoint.Name,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("GetAgentRuntimeEndpoint: %w", err)
+				}
+				endpoints = append(endpoints, newEndpointStatus(name, e))
+				continue
+			}
+			endpoints = append(endpoints, EndpointStatus{
+				Name:          name,
+				Arn:           aws.ToString(endpoint.AgentRuntimeEndpointArn),
+				Description:   aws.ToString(endpoint.Description),
+				Status:        string(endpoint.Status),
+				LiveVersion:   aws.ToString(endpoint.LiveVersion),
+				TargetVersion: aws.ToString(endpoint.TargetVersion),
+				CreatedAt:     endpoint.CreatedAt,
+				LastUpdatedAt: endpoint.LastUpdatedAt,
+			})
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Name < endpoints[j].Name
+	})
+	return endpoints, nil
+}
+
+func (s *Status) writeText(w io.Writer) error {
+	return writeStatusTable(w, s)
+}
+
+func writeStatusTable(w io.Writer, status *Status) error {
+	fmt.Fprintf(w, "Agent Runtime:  %s (%s)\n", status.AgentRuntimeName, status.AgentRuntimeID)
+	fmt.Fprintf(w, "ARN:            %s\n", status.AgentRuntimeArn)
+	fmt.Fprintf(w, "Latest Version: %s (%s)\n", status.LatestVersion, status.Status)
+	if status.ContainerURI != "" {
+		fmt.Fprintf(w, "Container URI:  %s\n", status.ContainerURI)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENDPOINT\tSTATUS\tLIVE\tTARGET\tCONTAINER URI\tLAST UPDATED")
+	for _, e := range status.Endpoints {
+		uri := e.LiveContainerURI
+		if e.TargetVersion != "" && e.TargetVersion != e.LiveVersion {
+			// show the image being rolled out
+			uri = e.TargetContainerURI
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Name,
+			e.Status,
+			orDash(e.LiveVersion),
+			orDash(e.TargetVersion),
+			orDash(uri),
+			formatTime(e.LastUpdatedAt),
+		)
+	}
+	return tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.RFC3339)
+}
@@ -0,0 +1,142 @@
+package acrun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStatus(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Output string
+		Check  func(t *testing.T, out string)
+	}{
+		{
+			Name:   "text",
+			Output: "text",
+			Check: func(t *testing.T, out string) {
+				require.Contains(t, out, "Latest Version: 3 (READY)")
+				require.Contains(t, out, "ENDPOINT")
+				require.Regexp(t, `DEFAULT\s+READY\s+3\s+3\s+123456789012\.dkr\.ecr\.us-west-2\.amazonaws\.com/acrun/sample:v3`, out)
+				require.Regexp(t, `current\s+UPDATING\s+1\s+2\s+123456789012\.dkr\.ecr\.us-west-2\.amazonaws\.com/acrun/sample:v2`, out)
+			},
+		},
+		{
+			Name:   "json",
+			Output: "json",
+			Check: func(t *testing.T, out string) {
+				var status Status
+				require.NoError(t, json.Unmarshal([]byte(out), &status))
+				require.Equal(t, "3", status.LatestVersion)
+				require.Equal(t, []EndpointStatus{
+					{
+						Name:               "DEFAULT",
+						Status:             "READY",
+						LiveVersion:        "3",
+						TargetVersion:      "3",
+						LiveContainerURI:   "123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample:v3",
+						TargetContainerURI: "123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample:v3",
+					},
+					{
+						Name:               "current",
+						Status:             "UPDATING",
+						LiveVersion:        "1",
+						TargetVersion:      "2",
+						LiveContainerURI:   "123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample:v1",
+						TargetContainerURI: "123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample:v2",
+					},
+				}, status.Endpoints)
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+			mockClient := NewMockBedrockAgentCoreClient(ctrl)
+			mockECRClient := NewMockECRClient(ctrl)
+			mockSTSClient := NewMockSTSClient(ctrl)
+			mockS3Client := NewMockS3Client(ctrl)
+
+			mockCtrlClient.EXPECT().
+				ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+					AgentRuntimes: []types.AgentRuntime{
+						{
+							AgentRuntimeId:   aws.String("test-runtime-id"),
+							AgentRuntimeName: aws.String("hosted_agent_dummy"),
+							AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+						},
+					},
+				}, nil)
+			mockCtrlClient.EXPECT().
+				GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, in *bedrockagentcorecontrol.GetAgentRuntimeInput, _ ...func(*bedrockagentcorecontrol.Options)) (*bedrockagentcorecontrol.GetAgentRuntimeOutput, error) {
+					version := aws.ToString(in.AgentRuntimeVersion)
+					if version == "" {
+						version = "3"
+					}
+					return &bedrockagentcorecontrol.GetAgentRuntimeOutput{
+						AgentRuntimeId:      aws.String("test-runtime-id"),
+						AgentRuntimeArn:     aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+						AgentRuntimeVersion: aws.String(version),
+						Status:              types.AgentRuntimeStatusReady,
+						AgentRuntimeArtifact: &types.AgentRuntimeArtifactMemberContainerConfiguration{
+							Value: types.ContainerConfiguration{
+								ContainerUri: aws.String("123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample:v" + version),
+							},
+						},
+					}, nil
+				}).
+				Times(3) // latest, version 1 and version 2
+			mockCtrlClient.EXPECT().
+				ListAgentRuntimeEndpoints(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&bedrockagentcorecontrol.ListAgentRuntimeEndpointsOutput{
+					RuntimeEndpoints: []types.AgentRuntimeEndpoint{
+						{
+							Name:          aws.String("current"),
+							Status:        types.AgentRuntimeEndpointStatusUpdating,
+							LiveVersion:   aws.String("1"),
+							TargetVersion: aws.String("2"),
+						},
+						{
+							Name:          aws.String("DEFAULT"),
+							Status:        types.AgentRuntimeEndpointStatusReady,
+							LiveVersion:   aws.String("3"),
+							TargetVersion: aws.String("3"),
+						},
+					},
+				}, nil)
+
+			app, err := NewWithClient(
+				context.Background(),
+				&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+				aws.Config{},
+				mockCtrlClient,
+				mockClient,
+				mockECRClient,
+				mockSTSClient,
+				mockS3Client,
+			)
+			require.NoError(t, err)
+
+			var stdout, stderr bytes.Buffer
+			app.SetOutput(&stdout, &stderr)
+
+			status, err := app.Status(context.Background(), &StatusOption{Output: tc.Output})
+			require.NoError(t, err)
+			require.Equal(t, "test-runtime-id", status.AgentRuntimeID)
+			tc.Check(t, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package acrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+// listTags returns the tags of the resource.
+func (app *App) listTags(ctx context.Context, arn string) (map[string]string, error) {
+	out, err := app.ctrlClient.ListTagsForResource(ctx, &bedrockagentcorecontrol.ListTagsForResourceInput{
+		ResourceArn: aws.String(arn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ListTagsForResource(%s): %w", arn, err)
+	}
+	if out.Tags == nil {
+		return map[string]string{}, nil
+	}
+	return out.Tags, nil
+}
+
+// diffTags returns the tags to set and the tag keys to remove to make current equal to desired.
+func diffTags(current, desired map[string]string) (map[string]string, []string) {
+	set := map[string]string{}
+	for k, v := range desired {
+		if cv, ok := current[k]; !ok || cv != v {
+			set[k] = v
+		}
+	}
+	var remove []string
+	for k := range current {
+		if _, ok := desired[k]; !ok {
+			remove = append(remove, k)
+		}
+	}
+	slices.Sort(remove)
+	return set, remove
+}
+
+// reconcileTags makes the tags of the resource equal to desired.
+func (app *App) reconcileTags(ctx context.Context, arn string, desired map[string]string, dryRun bool) error {
+	current, err := app.listTags(ctx, arn)
+	if err != nil {
+		return err
+	}
+	set, remove := diffTags(current, desired)
+	if len(set) == 0 && len(remove) == 0 {
+		app.logger.DebugContext(ctx, "tags are up to date", "arn", arn)
+		return nil
+	}
+	app.logger.InfoContext(ctx, "updating tags", "arn", arn, "set", slices.Sorted(maps.Keys(set)), "remove", remove)
+	if dryRun {
+		app.logger.DebugContext(ctx, "dry run: update tags skipped", "arn", arn)
+		return nil
+	}
+	if len(set) > 0 {
+		if _, err := app.ctrlClient.TagResource(ctx, &bedrockagentcorecontrol.TagResourceInput{
+			ResourceArn: aws.String(arn),
+			Tags:        set,
+		}); err != nil {
+			return fmt.Errorf("TagResource(%s): %w", arn, err)
+		}
+	}
+	if len(remove) > 0 {
+		if _, err := app.ctrlClient.UntagResource(ctx, &bedrockagentcorecontrol.UntagResourceInput{
+			ResourceArn: aws.String(arn),
+			TagKeys:     remove,
+		}); err != nil {
+			return fmt.Errorf("UntagResource(%s): %w", arn, err)
+		}
+	}
+	return nil
+}
+
+// reconcileEndpointTags makes the tags of the endpoints equal to desired.
+// Endpoints that do not exist yet are skipped in dry run mode; any other error is returned.
+func (app *App) reconcileEndpointTags(ctx context.Context, id string, endpointNames []string, desired map[string]string, dryRun bool) error {
+	for _, name := range endpointNames {
+		out, err := app.ctrlClient.GetAgentRuntimeEndpoint(ctx, &bedrockagentcorecontrol.GetAgentRuntimeEndpointInput{
+			AgentRuntimeId: aws.String(id),
+			EndpointName:   aws.String(name),
+		})
+		if err != nil {
+			var nfe *types.ResourceNotFoundException
+			if dryRun && errors.As(err, &nfe) {
+				app.logger.DebugContext(ctx, "dry run: endpoint does not exist yet, tags will be set after creation", "endpoint", name)
+				continue
+			}
+			return fmt.Errorf("GetAgentRuntimeEndpoint(%s): %w", name, err)
+		}
+		if err := app.reconcileTags(ctx, aws.ToString(out.AgentRuntimeEndpointArn), desired, dryRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutTags returns a shallow copy of the agent runtime without tags.
+// Tags are managed with TagResource, so they do not need a new version.
+func withoutTags(agentRuntime *AgentRuntime) *AgentRuntime {
+	if agentRuntime == nil {
+		return nil
+	}
+	c := *agentRuntime
+	c.Tags = nil
+	return &c
+}
@@ -0,0 +1,190 @@
+package acrun
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMarshalUnmarshalAgentRuntime_TagsKeepCase(t *testing.T) {
+	bs := []byte(`{"agentRuntimeName":"test","tags":{"CostCenter":"1234","project":"acrun"}}`)
+	def, err := unmarshalAgentRuntime(bs, true)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"CostCenter": "1234", "project": "acrun"}, def.Tags)
+
+	out, err := marshalAgentRuntime(def, "")
+	require.NoError(t, err)
+	require.JSONEq(t, string(bs), string(out))
+}
+
+func TestDiffTags(t *testing.T) {
+	set, remove := diffTags(
+		map[string]string{"Keep": "1", "Change": "old", "Remove": "x", "Another": "y"},
+		map[string]string{"Keep": "1", "Change": "new", "Add": "z"},
+	)
+	require.Equal(t, map[string]string{"Change": "new", "Add": "z"}, set)
+	require.Equal(t, []string{"Another", "Remove"}, remove)
+
+	set, remove = diffTags(map[string]string{"Keep": "1"}, map[string]string{"Keep": "1"})
+	require.Empty(t, set)
+	require.Empty(t, remove)
+}
+
+func TestReconcileTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app, mockCtrlClient, _ := newEndpointTestApp(t, ctrl)
+
+	arn := "arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"
+	mockCtrlClient.EXPECT().
+		ListTagsForResource(gomock.Any(), &bedrockagentcorecontrol.ListTagsForResourceInput{ResourceArn: aws.String(arn)}, gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListTagsForResourceOutput{
+			Tags: map[string]string{"CostCenter": "0000", "Owner": "someone"},
+		}, nil)
+	mockCtrlClient.EXPECT().
+		TagResource(gomock.Any(), &bedrockagentcorecontrol.TagResourceInput{
+			ResourceArn: aws.String(arn),
+			Tags:        map[string]string{"CostCenter": "1234", "Project": "acrun"},
+		}, gomock.Any()).
+		Return(&bedrockagentcorecontrol.TagResourceOutput{}, nil)
+	mockCtrlClient.EXPECT().
+		UntagResource(gomock.Any(), &bedrockagentcorecontrol.UntagResourceInput{
+			ResourceArn: aws.String(arn),
+			TagKeys:     []string{"Owner"},
+		}, gomock.Any()).
+		Return(&bedrockagentcorecontrol.UntagResourceOutput{}, nil)
+
+	err := app.reconcileTags(context.Background(), arn, map[string]string{"CostCenter": "1234", "Project": "acrun"}, false)
+	require.NoError(t, err)
+}
+
+func TestReconcileTags_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app, mockCtrlClient, _ := newEndpointTestApp(t, ctrl)
+
+	mockCtrlClient.EXPECT().
+		ListTagsForResource(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListTagsForResourceOutput{}, nil)
+
+	err := app.reconcileTags(context.Background(), "arn", map[string]string{"CostCenter": "1234"}, true)
+	require.NoError(t, err)
+}
+
+func TestReconcileEndpointTags_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app, mockCtrlClient, _ := newEndpointTestApp(t, ctrl)
+
+	// an endpoint that does not exist yet is skipped in dry run mode
+	mockCtrlClient.EXPECT().
+		GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, &types.ResourceNotFoundException{})
+	err := app.reconcileEndpointTags(context.Background(), "test-runtime-id", []string{"staging"}, map[string]string{"CostCenter": "1234"}, true)
+	require.NoError(t, err)
+
+	// other errors are returned even in dry run mode
+	mockCtrlClient.EXPECT().
+		GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, &types.AccessDeniedException{Message: aws.String("denied")})
+	err = app.reconcileEndpointTags(context.Background(), "test-runtime-id", []string{"staging"}, map[string]string{"CostCenter": "1234"}, true)
+	var ade *types.AccessDeniedException
+	require.ErrorAs(t, err, &ade)
+}
+
+func TestDiff_Tags(t *testing.T) {
+	cases := []struct {
+		name       string
+		remoteTags map[string]string
+		wantErr    error
+	}{
+		{name: "same", remoteTags: map[string]string{"CostCenter": "1234", "Project": "acrun"}},
+		{name: "different", remoteTags: map[string]string{"costcenter": "1234"}, wantErr: ErrDiff},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+			mockCtrlClient.EXPECT().
+				ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+					AgentRuntimes: []types.AgentRuntime{
+						{
+							AgentRuntimeId:   aws.String("test-runtime-id"),
+							AgentRuntimeName: aws.String("hosted_agent_dummy"),
+							AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+						},
+					},
+				}, nil)
+			mockCtrlClient.EXPECT().
+				GetAgentRuntimeEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&bedrockagentcorecontrol.GetAgentRuntimeEndpointOutput{
+					TargetVersion: aws.String("1"),
+				}, nil)
+			mockCtrlClient.EXPECT().
+				GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+					AgentRuntimeId:      aws.String("test-runtime-id"),
+					AgentRuntimeName:    aws.String("hosted_agent_dummy"),
+					AgentRuntimeArn:     aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+					AgentRuntimeVersion: aws.String("1"),
+					RoleArn:             aws.String("arn:aws:iam::123456789012:role/service-role/DummyServiceRole"),
+					AgentRuntimeArtifact: &types.AgentRuntimeArtifactMemberContainerConfiguration{
+						Value: types.ContainerConfiguration{
+							ContainerUri: aws.String("123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample-mcp:dev"),
+						},
+					},
+					NetworkConfiguration: &types.NetworkConfiguration{
+						NetworkMode: types.NetworkModePublic,
+					},
+					ProtocolConfiguration: &types.ProtocolConfiguration{
+						ServerProtocol: types.ServerProtocolMcp,
+					},
+					EnvironmentVariables: map[string]string{
+						"env": "dev",
+					},
+					AuthorizerConfiguration: &types.AuthorizerConfigurationMemberCustomJWTAuthorizer{
+						Value: types.CustomJWTAuthorizerConfiguration{
+							DiscoveryUrl:    aws.String("https://example.com/.well-known/openid-configuration"),
+							AllowedAudience: []string{"example_audience"},
+							AllowedClients:  []string{"example_client"},
+						},
+					},
+				}, nil)
+			mockCtrlClient.EXPECT().
+				ListTagsForResource(gomock.Any(), &bedrockagentcorecontrol.ListTagsForResourceInput{
+					ResourceArn: aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+				}, gomock.Any()).
+				Return(&bedrockagentcorecontrol.ListTagsForResourceOutput{Tags: c.remoteTags}, nil)
+
+			app, err := NewWithClient(
+				context.Background(),
+				&GlobalOption{AgentRuntime: "testdata/agent_runtime_with_tags.json"},
+				aws.Config{},
+				mockCtrlClient,
+				NewMockBedrockAgentCoreClient(ctrl),
+				NewMockECRClient(ctrl),
+				NewMockSTSClient(ctrl),
+				NewMockS3Client(ctrl),
+			)
+			require.NoError(t, err)
+			var stdout, stderr bytes.Buffer
+			app.SetOutput(&stdout, &stderr)
+
+			_, err = app.Diff(context.Background(), &DiffOption{ExitCode: true})
+			if c.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, c.wantErr)
+		})
+	}
+}
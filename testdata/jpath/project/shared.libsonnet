@@ -0,0 +1 @@
+{ source: 'project' }
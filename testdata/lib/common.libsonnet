@@ -0,0 +1,3 @@
+{
+  roleArn: 'arn:aws:iam::123456789012:role/service-role/DummyServiceRole',
+}
@@ -0,0 +1,182 @@
+package acrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+)
+
+// VersionsOption represents the options for the versions command.
+type VersionsOption struct {
+	List VersionsListOption `cmd:"" default:"withargs" help:"List versions of the agent runtime (default)."`
+	Show VersionsShowOption `cmd:"" help:"Show the configuration of a version."`
+}
+
+type VersionsListOption struct {
+	Output string `name:"output" short:"o" help:"output format (text, json)" default:"text" enum:"text,json"`
+	Limit  int    `name:"limit" help:"number of recent versions to show. 0 means all. Each version costs a GetAgentRuntime call for its creation time and artifact" default:"20"`
+}
+
+type VersionsShowOption struct {
+	Version string `arg:"" help:"the version number to show"`
+	Format  string `help:"output format (json, jsonnet)" default:"json" enum:"json,jsonnet"`
+}
+
+// VersionInfo is a version of the agent runtime.
+type VersionInfo struct {
+	Version       string     `json:"version"`
+	Status        string     `json:"status"`
+	CreatedAt     *time.Time `json:"createdAt,omitempty"`
+	LastUpdatedAt *time.Time `json:"lastUpdatedAt,omitempty"`
+	Artifact      string     `json:"artifact,omitempty"`
+	Description   string     `json:"description,omitempty"`
+	// Endpoints is the names of the endpoints that point to the version.
+	Endpoints []string `json:"endpoints"`
+}
+
+func (app *App) VersionsList(ctx context.Context, opt *VersionsListOption) (*VersionsListResult, error) {
+	agentRuntime, err := app.loadAgentRuntimeFile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load agent runtime file: %w", err)
+	}
+	id, err := app.GetAgentRuntimeIDByName(ctx, *agentRuntime.AgentRuntimeName)
+	if err != nil {
+		return nil, fmt.Errorf("get agent runtime ID: %w", err)
+	}
+	summaries, err := app.listAgentRuntimeVersions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if opt.Limit > 0 && len(summaries) > opt.Limit {
+		summaries = summaries[:opt.Limit]
+	}
+	endpoints, err := app.listAgentRuntimeEndpoints(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	endpointsByVersion := make(map[string][]string, len(endpoints))
+	for _, e := range endpoints {
+		v := e.TargetVersion
+		if v == "" {
+			v = e.LiveVersion
+		}
+		endpointsByVersion[v] = append(endpointsByVersion[v], e.Name)
+	}
+
+	versions := make([]VersionInfo, 0, len(summaries))
+	for _, summary := range summaries {
+		version := aws.ToString(summary.AgentRuntimeVersion)
+		info := VersionInfo{
+			Version:       version,
+			Status:        string(summary.Status),
+			LastUpdatedAt: summary.LastUpdatedAt,
+			Description:   aws.ToString(summary.Description),
+			Endpoints:     endpointsByVersion[version],
+		}
+		if info.Endpoints == nil {
+			info.Endpoints = []string{}
+		}
+		// ListAgentRuntimeVersions does not include the creation time and the artifact
+		out, err := app.ctrlClient.GetAgentRuntime(ctx, &bedrockagentcorecontrol.GetAgentRuntimeInput{
+			AgentRuntimeId:      aws.String(id),
+			AgentRuntimeVersion: aws.String(version),
+		})
+		if err != nil {
+			app.logger.WarnContext(ctx, "failed to get agent runtime version", "version", version, "error", err)
+		} else {
+			info.CreatedAt = out.CreatedAt
+			info.Artifact = describeArtifact(out.AgentRuntimeArtifact)
+		}
+		versions = append(versions, info)
+	}
+
+	result := &VersionsListResult{
+		AgentRuntimeName: *agentRuntime.AgentRuntimeName,
+		AgentRuntimeID:   id,
+		Versions:         versions,
+	}
+	return result, app.writeResult(opt.Output, result)
+}
+
+// VersionsShow returns the configuration of the version.
+func (app *App) VersionsShow(ctx context.Context, opt *VersionsShowOption) (*AgentRuntime, error) {
+	if _, err := strconv.ParseUint(opt.Version, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid version %q: must be a version number", opt.Version)
+	}
+	agentRuntime, err := app.loadAgentRuntimeFile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load agent runtime file: %w", err)
+	}
+	resp, err := app.GetAgentRuntime(ctx, agentRuntime.AgentRuntimeName, aws.String(opt.Version))
+	if err != nil {
+		return nil, fmt.Errorf("get agent runtime version %s: %w", opt.Version, err)
+	}
+	remote, err := newAgentRuntimeFromResponse(resp)
+	if err != nil {
+		return nil, fmt.Errorf("newAgentRuntimeFromResponse: %w", err)
+	}
+	return remote, app.renderAgentRuntime(remote, opt.Format)
+}
+
+// listAgentRuntimeVersions pages through all versions and returns them in descending order.
+func (app *App) listAgentRuntimeVersions(ctx context.Context, id string) ([]types.AgentRuntime, error) {
+	var versions []types.AgentRuntime
+	p := bedrockagentcorecontrol.NewListAgentRuntimeVersionsPaginator(app.ctrlClient, &bedrockagentcorecontrol.ListAgentRuntimeVersionsInput{
+		AgentRuntimeId: aws.String(id),
+	})
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ListAgentRuntimeVersions: %w", err)
+		}
+		versions = append(versions, out.AgentRuntimes...)
+	}
+	slices.SortFunc(versions, func(a, b types.AgentRuntime) int {
+		var va, vb int
+		fmt.Sscanf(aws.ToString(a.AgentRuntimeVersion), "%d", &va) //nolint:errcheck
+		fmt.Sscanf(aws.ToString(b.AgentRuntimeVersion), "%d", &vb) //nolint:errcheck
+		return vb - va
+	})
+	return versions, nil
+}
+
+// describeArtifact returns a short description of the artifact: the container URI or the S3 location of the code.
+func describeArtifact(artifact types.AgentRuntimeArtifact) string {
+	if uri := extractContainerURI(artifact); uri != "" {
+		return uri
+	}
+	v, ok := artifact.(*types.AgentRuntimeArtifactMemberCodeConfiguration)
+	if !ok {
+		return ""
+	}
+	code, ok := v.Value.Code.(*types.CodeMemberS3)
+	if !ok {
+		return ""
+	}
+	return "s3://" + aws.ToString(code.Value.Bucket) + "/" + aws.ToString(code.Value.Prefix)
+}
+
+func writeVersionsTable(w io.Writer, versions []VersionInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tSTATUS\tCREATED\tENDPOINTS\tARTIFACT\tDESCRIPTION")
+	for _, v := range versions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			v.Version,
+			v.Status,
+			formatTime(v.CreatedAt),
+			orDash(strings.Join(v.Endpoints, ",")),
+			orDash(v.Artifact),
+			orDash(v.Description),
+		)
+	}
+	return tw.Flush()
+}
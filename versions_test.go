@@ -0,0 +1,160 @@
+package acrun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentcorecontrol/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newVersionsTestApp(t *testing.T, mockCtrlClient *MockBedrockAgentCoreControlClient, ctrl *gomock.Controller) (*App, *bytes.Buffer) {
+	t.Helper()
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimesOutput{
+			AgentRuntimes: []types.AgentRuntime{
+				{
+					AgentRuntimeId:   aws.String("test-runtime-id"),
+					AgentRuntimeName: aws.String("hosted_agent_dummy"),
+					AgentRuntimeArn:  aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+				},
+			},
+		}, nil)
+	app, err := NewWithClient(
+		context.Background(),
+		&GlobalOption{AgentRuntime: "testdata/agent_runtime.json"},
+		aws.Config{},
+		mockCtrlClient,
+		NewMockBedrockAgentCoreClient(ctrl),
+		NewMockECRClient(ctrl),
+		NewMockSTSClient(ctrl),
+		NewMockS3Client(ctrl),
+	)
+	require.NoError(t, err)
+	var stdout, stderr bytes.Buffer
+	app.SetOutput(&stdout, &stderr)
+	return app, &stdout
+}
+
+func TestVersionsList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	app, stdout := newVersionsTestApp(t, mockCtrlClient, ctrl)
+
+	// two pages
+	gomock.InOrder(
+		mockCtrlClient.EXPECT().
+			ListAgentRuntimeVersions(gomock.Any(), &bedrockagentcorecontrol.ListAgentRuntimeVersionsInput{
+				AgentRuntimeId: aws.String("test-runtime-id"),
+			}, gomock.Any()).
+			Return(&bedrockagentcorecontrol.ListAgentRuntimeVersionsOutput{
+				AgentRuntimes: []types.AgentRuntime{
+					{AgentRuntimeVersion: aws.String("1"), Status: types.AgentRuntimeStatusReady},
+					{AgentRuntimeVersion: aws.String("2"), Status: types.AgentRuntimeStatusReady, Description: aws.String("second")},
+				},
+				NextToken: aws.String("next"),
+			}, nil),
+		mockCtrlClient.EXPECT().
+			ListAgentRuntimeVersions(gomock.Any(), &bedrockagentcorecontrol.ListAgentRuntimeVersionsInput{
+				AgentRuntimeId: aws.String("test-runtime-id"),
+				NextToken:      aws.String("next"),
+			}, gomock.Any()).
+			Return(&bedrockagentcorecontrol.ListAgentRuntimeVersionsOutput{
+				AgentRuntimes: []types.AgentRuntime{
+					{AgentRuntimeVersion: aws.String("10"), Status: types.AgentRuntimeStatusUpdating},
+				},
+			}, nil),
+	)
+	mockCtrlClient.EXPECT().
+		ListAgentRuntimeEndpoints(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&bedrockagentcorecontrol.ListAgentRuntimeEndpointsOutput{
+			RuntimeEndpoints: []types.AgentRuntimeEndpoint{
+				{Name: aws.String("DEFAULT"), LiveVersion: aws.String("10")},
+				{Name: aws.String("current"), LiveVersion: aws.String("1"), TargetVersion: aws.String("2")},
+			},
+		}, nil)
+	createdAt := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	mockCtrlClient.EXPECT().
+		GetAgentRuntime(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, in *bedrockagentcorecontrol.GetAgentRuntimeInput, _ ...func(*bedrockagentcorecontrol.Options)) (*bedrockagentcorecontrol.GetAgentRuntimeOutput, error) {
+			return &bedrockagentcorecontrol.GetAgentRuntimeOutput{
+				AgentRuntimeVersion: in.AgentRuntimeVersion,
+				CreatedAt:           aws.Time(createdAt),
+				AgentRuntimeArtifact: &types.AgentRuntimeArtifactMemberContainerConfiguration{
+					Value: types.ContainerConfiguration{
+						ContainerUri: aws.String("123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample:v" + aws.ToString(in.AgentRuntimeVersion)),
+					},
+				},
+			}, nil
+		}).
+		Times(2)
+
+	result, err := app.VersionsList(context.Background(), &VersionsListOption{Output: "json", Limit: 2})
+	require.NoError(t, err)
+
+	var out VersionsListResult
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+	require.Equal(t, *result, out)
+	require.Equal(t, "hosted_agent_dummy", out.AgentRuntimeName)
+	require.Equal(t, "test-runtime-id", out.AgentRuntimeID)
+	require.Equal(t, []VersionInfo{
+		{
+			Version:   "10",
+			Status:    "UPDATING",
+			CreatedAt: aws.Time(createdAt),
+			Artifact:  "123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample:v10",
+			Endpoints: []string{"DEFAULT"},
+		},
+		{
+			Version:     "2",
+			Status:      "READY",
+			CreatedAt:   aws.Time(createdAt),
+			Artifact:    "123456789012.dkr.ecr.us-west-2.amazonaws.com/acrun/sample:v2",
+			Description: "second",
+			Endpoints:   []string{"current"},
+		},
+	}, out.Versions)
+}
+
+func TestVersionsShow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCtrlClient := NewMockBedrockAgentCoreControlClient(ctrl)
+	app, stdout := newVersionsTestApp(t, mockCtrlClient, ctrl)
+
+	local, err := app.loadAgentRuntimeFile(context.Background())
+	require.NoError(t, err)
+	mockCtrlClient.EXPECT().
+		GetAgentRuntime(gomock.Any(), &bedrockagentcorecontrol.GetAgentRuntimeInput{
+			AgentRuntimeId:      aws.String("test-runtime-id"),
+			AgentRuntimeVersion: aws.String("3"),
+		}, gomock.Any()).
+		Return(&bedrockagentcorecontrol.GetAgentRuntimeOutput{
+			AgentRuntimeArn:         aws.String("arn:aws:bedrock-agentcore:us-west-2:123456789012:runtime/test-runtime-id"),
+			AgentRuntimeId:          aws.String("test-runtime-id"),
+			AgentRuntimeName:        local.AgentRuntimeName,
+			AgentRuntimeVersion:     aws.String("3"),
+			RoleArn:                 local.RoleArn,
+			AgentRuntimeArtifact:    local.AgentRuntimeArtifact,
+			NetworkConfiguration:    local.NetworkConfiguration,
+			ProtocolConfiguration:   local.ProtocolConfiguration,
+			EnvironmentVariables:    local.EnvironmentVariables,
+			AuthorizerConfiguration: local.AuthorizerConfiguration,
+			Status:                  types.AgentRuntimeStatusReady,
+		}, nil)
+
+	_, err = app.VersionsShow(context.Background(), &VersionsShowOption{Version: "3", Format: "json"})
+	require.NoError(t, err)
+
+	expected, err := marshalAgentRuntime(local, "  ")
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), stdout.String())
+}
@@ -3,17 +3,62 @@ package acrun
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 )
 
+// TerminalStatusError is returned by a Waiter when the resource reaches one of its TerminalStatuses.
+type TerminalStatusError struct {
+	Resource string
+	Status   string
+	Reason   string
+}
+
+func (e *TerminalStatusError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("%s is in %s status", e.Resource, e.Status)
+	}
+	return fmt.Sprintf("%s is in %s status: %s", e.Resource, e.Status, e.Reason)
+}
+
+// Waiter polls Checker until it reports done, returns an error, or MaxDuration elapses.
+// With TerminalStatuses, the Waiter also stops as soon as the resource reaches a terminal failure status.
 type Waiter struct {
 	MaxDuration   time.Duration
 	CheckInterval time.Duration
 	LogInterval   time.Duration
 	LogMessage    string
 	LogAttributes []any
-	Checker       func(context.Context) ([]any, bool, error)
+	// Checker returns the attributes for the progress log and whether the wait is done.
+	// The "status" and "reason" attributes are the status of the resource and its failure reason.
+	// Any error stops the Waiter.
+	Checker func(context.Context) ([]any, bool, error)
+	// TerminalStatuses are the failure statuses of the resource, e.g. CREATE_FAILED and UPDATE_FAILED.
+	// When the "status" attribute returned by Checker is one of them, Wait returns a TerminalStatusError.
+	TerminalStatuses []string
+	// Resource names the resource in the TerminalStatusError.
+	Resource string
+	// Logger is the logger for progress messages. The default is slog.Default().
+	Logger *slog.Logger
+}
+
+// terminalStatusError returns a TerminalStatusError if the "status" attribute is one of TerminalStatuses.
+func (w *Waiter) terminalStatusError(attrs []any) error {
+	var status, reason string
+	for i := 0; i+1 < len(attrs); i += 2 {
+		switch attrs[i] {
+		case "status":
+			status = fmt.Sprint(attrs[i+1])
+		case "reason":
+			reason = fmt.Sprint(attrs[i+1])
+		}
+	}
+	if status == "" || !slices.Contains(w.TerminalStatuses, status) {
+		return nil
+	}
+	return &TerminalStatusError{Resource: w.Resource, Status: status, Reason: reason}
 }
 
 func (w *Waiter) Wait(ctx context.Context) error {
@@ -32,6 +77,10 @@ func (w *Waiter) Wait(ctx context.Context) error {
 	if w.LogInterval < w.CheckInterval {
 		w.LogInterval = w.CheckInterval
 	}
+	logger := w.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
 	deadlineCtx, cancel := context.WithTimeout(ctx, w.MaxDuration)
 	defer cancel()
 	ticker := time.NewTicker(w.CheckInterval)
@@ -45,7 +94,14 @@ func (w *Waiter) Wait(ctx context.Context) error {
 			return errors.New("waiter: context deadline exceeded")
 		case <-ticker.C:
 			attrs, done, err := w.Checker(ctx)
+			if err == nil {
+				err = w.terminalStatusError(attrs)
+			}
 			if err != nil {
+				var tse *TerminalStatusError
+				if errors.As(err, &tse) {
+					logger.ErrorContext(ctx, "reached terminal failure status", append(append([]any{}, w.LogAttributes...), "status", tse.Status, "reason", tse.Reason)...)
+				}
 				return err
 			}
 			if len(attrs) > 0 {
@@ -56,7 +112,7 @@ func (w *Waiter) Wait(ctx context.Context) error {
 				return nil
 			}
 		case <-logTicker.C:
-			slog.InfoContext(ctx, w.LogMessage, currentAttrs...)
+			logger.InfoContext(ctx, w.LogMessage, currentAttrs...)
 		}
 	}
 }
@@ -0,0 +1,34 @@
+package acrun
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaiter_TerminalStatuses(t *testing.T) {
+	calls := 0
+	waiter := &Waiter{
+		MaxDuration:      time.Second,
+		CheckInterval:    time.Millisecond,
+		LogMessage:       "waiting",
+		Resource:         "agent runtime endpoint dev",
+		TerminalStatuses: []string{"CREATE_FAILED", "UPDATE_FAILED"},
+		Checker: func(ctx context.Context) ([]any, bool, error) {
+			calls++
+			if calls == 1 {
+				return []any{"status", "UPDATING", "reason", ""}, false, nil
+			}
+			return []any{"status", "UPDATE_FAILED", "reason", "image not found"}, false, nil
+		},
+	}
+	err := waiter.Wait(context.Background())
+	var tse *TerminalStatusError
+	require.True(t, errors.As(err, &tse))
+	require.Equal(t, &TerminalStatusError{Resource: "agent runtime endpoint dev", Status: "UPDATE_FAILED", Reason: "image not found"}, tse)
+	require.Equal(t, 2, calls)
+	require.EqualError(t, err, "agent runtime endpoint dev is in UPDATE_FAILED status: image not found")
+}